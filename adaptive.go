@@ -0,0 +1,207 @@
+package channels
+
+import (
+	"context"
+	"sync/atomic"
+)
+
+// adaptiveResizeAfter is the number of consecutive observations of pressure
+// or idleness after which AdaptiveBuffer resizes its buffer.
+const adaptiveResizeAfter = 3
+
+// AdaptiveBuffer takes an input channel and returns an output channel that
+// emits the values from the input channel in the same order, buffering them
+// in memory so that bursts in the input channel don't block on slow
+// consumers. The buffer has a limit, which starts at minSize, and the input
+// channel is no longer consumed while the buffer holds as many values as its
+// limit, so values are never dropped.
+//
+// The limit adapts to the observed occupancy of the buffer:
+//
+//   - whenever the consumer takes a value from a full buffer and the input
+//     channel has another value ready right away, the buffer is considered
+//     under pressure. After 3 consecutive observations of pressure, the limit
+//     is doubled, up to maxSize.
+//   - whenever the consumer takes the last value from the buffer and the
+//     input channel has no value ready, the buffer is considered idle. After 3
+//     consecutive observations of idleness, the limit is halved, down to
+//     minSize.
+//
+// If minSize is lower than 1, it's treated as 1, and if maxSize is lower than
+// minSize, it's treated as minSize.
+//
+// The output channel is unbuffered.
+//
+// This is a non-blocking function: it launches a goroutine and returns the
+// channel for consumption. In order to stop the inner goroutine, one can close
+// the input channel or cancel the provided context.
+//
+// The output channel is always closed on cancellation or after the input
+// channel is closed and all buffered values are sent. Buffered values are
+// discarded on cancellation.
+func AdaptiveBuffer[T any](ctx context.Context, in <-chan T, minSize, maxSize int) <-chan T {
+	if minSize < 1 {
+		minSize = 1
+	}
+	if maxSize < minSize {
+		maxSize = minSize
+	}
+	out := make(chan T)
+	go func() {
+		defer close(out)
+		var (
+			limit           = minSize
+			buf             = make([]T, 0, limit)
+			queue           = buf
+			pressured, idle int
+		)
+		for in != nil || len(queue) > 0 {
+			var (
+				recvCh <-chan T
+				sendCh chan<- T
+				next   T
+			)
+			if len(queue) < limit {
+				recvCh = in
+			}
+			if len(queue) > 0 {
+				sendCh = out
+				next = queue[0]
+			}
+			select {
+			case v, ok := <-recvCh:
+				if !ok {
+					in = nil
+					continue
+				}
+				queue = append(queue, v)
+			case sendCh <- next:
+				wasFull := len(queue) == limit
+				var zero T
+				queue[0] = zero
+				queue = queue[1:]
+				if wasFull && in != nil {
+					select {
+					case v, ok := <-in:
+						if ok {
+							queue = append(queue, v)
+							idle = 0
+							if pressured++; pressured == adaptiveResizeAfter {
+								limit = min(limit*2, maxSize)
+								pressured = 0
+							}
+							continue
+						}
+						in = nil
+					default:
+					}
+				}
+				if len(queue) == 0 {
+					pressured = 0
+					if idle++; idle == adaptiveResizeAfter {
+						if limit /= 2; limit < minSize {
+							limit = minSize
+						}
+						idle = 0
+					}
+					// reuse the backing array, unless the limit
+					// changed since it was allocated, so a shrunk
+					// buffer releases its memory.
+					if cap(buf) != limit {
+						buf = make([]T, 0, limit)
+					}
+					queue = buf
+				}
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return out
+}
+
+// Prefetch takes an input channel and returns an output channel that emits
+// the values from the input channel in the same order, reading up to depth
+// values ahead of the consumer. This smooths out latency spikes in slow
+// sources, such as IO-bound ones, as values are read while the consumer is
+// busy with the previous ones. Once depth values are read ahead, the input
+// channel is no longer consumed until the consumer catches up. If depth is
+// lower than 1, it's treated as 1.
+//
+// The output channel is unbuffered, so the values read ahead are held by the
+// inner goroutine.
+//
+// This is a non-blocking function: it launches a goroutine and returns the
+// channel for consumption. In order to stop the inner goroutine, one can close
+// the input channel or cancel the provided context.
+//
+// The output channel is always closed on cancellation or after the input
+// channel is closed and all values read ahead are sent. Values read ahead are
+// discarded on cancellation.
+func Prefetch[T any](ctx context.Context, in <-chan T, depth int) <-chan T {
+	return AdaptiveBuffer(ctx, in, depth, depth)
+}
+
+// DropOldestBuffer takes an input channel and returns an output channel that
+// emits the values from the input channel in the same order, buffering up to
+// size values in memory. When the buffer is full, the oldest buffered value is
+// discarded to make room for the new one, so the input channel is always
+// consumed and producers never block on slow consumers, at the cost of losing
+// stale values. If size is lower than 1, it's treated as 1.
+//
+// The returned function reports the total number of values discarded so far,
+// and is safe for concurrent use.
+//
+// The output channel is unbuffered.
+//
+// This is a non-blocking function: it launches a goroutine and returns the
+// channel for consumption. In order to stop the inner goroutine, one can close
+// the input channel or cancel the provided context.
+//
+// The output channel is always closed on cancellation or after the input
+// channel is closed and all buffered values are sent. Buffered values are
+// discarded on cancellation, and aren't counted as dropped.
+func DropOldestBuffer[T any](ctx context.Context, in <-chan T, size int) (<-chan T, func() int) {
+	if size < 1 {
+		size = 1
+	}
+	var dropped int64
+	out := make(chan T)
+	go func() {
+		defer close(out)
+		var queue []T
+		for in != nil || len(queue) > 0 {
+			var (
+				sendCh chan<- T
+				next   T
+			)
+			if len(queue) > 0 {
+				sendCh = out
+				next = queue[0]
+			}
+			select {
+			case v, ok := <-in:
+				if !ok {
+					in = nil
+					continue
+				}
+				if len(queue) == size {
+					var zero T
+					queue[0] = zero
+					queue = queue[1:]
+					atomic.AddInt64(&dropped, 1)
+				}
+				queue = append(queue, v)
+			case sendCh <- next:
+				var zero T
+				queue[0] = zero
+				queue = queue[1:]
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return out, func() int {
+		return int(atomic.LoadInt64(&dropped))
+	}
+}
@@ -0,0 +1,265 @@
+package channels
+
+import (
+	"context"
+	"reflect"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestAdaptiveBuffer(t *testing.T) {
+	t.Parallel()
+	in := make(chan int)
+	go func() {
+		defer close(in)
+		for i := 0; i < 100; i++ {
+			in <- i
+		}
+	}()
+
+	out := AdaptiveBuffer(context.TODO(), in, 2, 16)
+	var values []int
+	Consume(context.TODO(), out, func(v int) bool {
+		if v%10 == 0 {
+			time.Sleep(time.Millisecond)
+		}
+		values = append(values, v)
+		return true
+	})
+
+	expected := make([]int, 100)
+	for i := range expected {
+		expected[i] = i
+	}
+	if !reflect.DeepEqual(values, expected) {
+		t.Errorf("wrong values returned\nwant %#v\ngot  %#v", expected, values)
+	}
+}
+
+func TestAdaptiveBufferResizesWithOccupancy(t *testing.T) {
+	t.Parallel()
+	in := make(chan int)
+	out := AdaptiveBuffer(context.TODO(), in, 1, 8)
+
+	var sent int64
+	stop := make(chan struct{})
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		for i := 0; ; i++ {
+			select {
+			case in <- i:
+				atomic.AddInt64(&sent, 1)
+			case <-stop:
+				return
+			}
+		}
+	}()
+
+	received := 0
+	receive := func(n int, wait time.Duration) {
+		t.Helper()
+		for i := 0; i < n; i++ {
+			if v := <-out; v != received {
+				t.Fatalf("wrong value returned\nwant %d\ngot  %d", received, v)
+			}
+			received++
+			time.Sleep(wait)
+		}
+	}
+	// buffered gives the producer time to fill the buffer and returns the
+	// number of values it holds.
+	buffered := func() int {
+		time.Sleep(20 * time.Millisecond)
+		return int(atomic.LoadInt64(&sent)) - received
+	}
+
+	// the producer always has a value ready, so the limit doubles after
+	// every third value taken from the full buffer, up to maxSize.
+	for _, expected := range []int{1, 2, 4, 8, 8} {
+		if n := buffered(); n != expected {
+			t.Fatalf("wrong number of buffered values under pressure\nwant %d\ngot  %d", expected, n)
+		}
+		receive(3, 10*time.Millisecond)
+	}
+
+	// without a producer, draining the buffer three times halves the limit.
+	close(stop)
+	<-stopped
+	receive(buffered(), 0)
+	for i := 0; i < 2; i++ {
+		in <- received
+		receive(1, 0)
+	}
+
+	for i := 0; i < 4; i++ {
+		select {
+		case in <- received + i:
+		case <-time.After(time.Second):
+			t.Fatalf("input blocked after %d values", i)
+		}
+	}
+	select {
+	case in <- received + 4:
+		t.Fatal("unexpected send while the buffer is full")
+	case <-time.After(50 * time.Millisecond):
+	}
+	close(in)
+
+	expected := []int{received, received + 1, received + 2, received + 3}
+	if values := ToSlice(context.TODO(), out); !reflect.DeepEqual(values, expected) {
+		t.Errorf("wrong values returned\nwant %#v\ngot  %#v", expected, values)
+	}
+}
+
+func TestAdaptiveBufferWithContextCancellation(t *testing.T) {
+	t.Parallel()
+	ch := startGenerator(t, 0, func(p int) (int, bool) {
+		return p + 1, true
+	}, func() { time.Sleep(time.Second) })
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	values := ToSlice(context.TODO(), AdaptiveBuffer(ctx, ch, 1, 10))
+	if values != nil {
+		t.Errorf("unexpected non-nil slice: %#v", values)
+	}
+}
+
+func TestPrefetch(t *testing.T) {
+	t.Parallel()
+	in := make(chan int)
+	out := Prefetch(context.TODO(), in, 4)
+	for i := 0; i < 4; i++ {
+		select {
+		case in <- i:
+		case <-time.After(time.Second):
+			t.Fatalf("input blocked after %d values", i)
+		}
+	}
+	select {
+	case in <- 4:
+		t.Fatal("unexpected read beyond the prefetch depth")
+	case <-time.After(50 * time.Millisecond):
+	}
+	close(in)
+
+	expected := []int{0, 1, 2, 3}
+	if values := ToSlice(context.TODO(), out); !reflect.DeepEqual(values, expected) {
+		t.Errorf("wrong values returned\nwant %#v\ngot  %#v", expected, values)
+	}
+}
+
+func TestPrefetchWithContextCancellation(t *testing.T) {
+	t.Parallel()
+	in := make(chan int)
+	ctx, cancel := context.WithCancel(context.Background())
+	out := Prefetch(ctx, in, 4)
+	in <- 1
+	in <- 2
+	cancel()
+
+	// wait for the prefetched values to be discarded.
+	time.Sleep(10 * time.Millisecond)
+	if values := ToSlice(context.TODO(), out); values != nil {
+		t.Errorf("unexpected non-nil slice: %#v", values)
+	}
+}
+
+func BenchmarkPrefetch(b *testing.B) {
+	// slowSource emits n values, stalling on every 10th value to simulate
+	// latency spikes in an IO-bound source.
+	slowSource := func(n int) <-chan int {
+		ch := make(chan int)
+		go func() {
+			defer close(ch)
+			for i := 0; i < n; i++ {
+				if i%10 == 0 {
+					time.Sleep(10 * time.Millisecond)
+				}
+				ch <- i
+			}
+		}()
+		return ch
+	}
+	consume := func(ch <-chan int) {
+		for range ch {
+			time.Sleep(time.Millisecond)
+		}
+	}
+
+	b.Run("without prefetch", func(b *testing.B) {
+		consume(slowSource(b.N))
+	})
+	b.Run("with prefetch", func(b *testing.B) {
+		consume(Prefetch(context.TODO(), slowSource(b.N), 16))
+	})
+}
+
+func TestDropOldestBuffer(t *testing.T) {
+	t.Parallel()
+	in := make(chan int)
+	out, dropped := DropOldestBuffer(context.TODO(), in, 3)
+	for i := 0; i < 10; i++ {
+		select {
+		case in <- i:
+		case <-time.After(time.Second):
+			t.Fatalf("input blocked after %d values", i)
+		}
+	}
+	close(in)
+
+	expected := []int{7, 8, 9}
+	if values := ToSlice(context.TODO(), out); !reflect.DeepEqual(values, expected) {
+		t.Errorf("wrong values returned\nwant %#v\ngot  %#v", expected, values)
+	}
+	if n := dropped(); n != 7 {
+		t.Errorf("wrong number of dropped values\nwant 7\ngot  %d", n)
+	}
+}
+
+func TestDropOldestBufferWithSlowConsumer(t *testing.T) {
+	t.Parallel()
+	in := make(chan int)
+	go func() {
+		defer close(in)
+		for i := 0; i < 100; i++ {
+			in <- i
+		}
+	}()
+
+	out, dropped := DropOldestBuffer(context.TODO(), in, 5)
+	var values []int
+	Consume(context.TODO(), out, func(v int) bool {
+		time.Sleep(time.Millisecond)
+		values = append(values, v)
+		return true
+	})
+
+	if len(values) == 0 || values[len(values)-1] != 99 {
+		t.Errorf("newest value didn't survive: %#v", values)
+	}
+	for i := 1; i < len(values); i++ {
+		if values[i] <= values[i-1] {
+			t.Fatalf("values out of order: %#v", values)
+		}
+	}
+	if n := dropped(); n != 100-len(values) {
+		t.Errorf("wrong number of dropped values\nwant %d\ngot  %d", 100-len(values), n)
+	}
+}
+
+func TestDropOldestBufferWithContextCancellation(t *testing.T) {
+	t.Parallel()
+	ch := startGenerator(t, 0, func(p int) (int, bool) {
+		return p + 1, true
+	}, func() { time.Sleep(time.Second) })
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	out, _ := DropOldestBuffer(ctx, ch, 2)
+	if values := ToSlice(context.TODO(), out); values != nil {
+		t.Errorf("unexpected non-nil slice: %#v", values)
+	}
+}
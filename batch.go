@@ -0,0 +1,248 @@
+package channels
+
+import (
+	"context"
+	"time"
+)
+
+// BufferUntil takes an input channel and a signal channel, and returns a
+// channel that emits the values accumulated from the input channel every time
+// the signal channel fires. If no values were received since the previous
+// signal, an empty batch is emitted.
+//
+// When the input channel is closed, the values accumulated since the last
+// signal, if any, are emitted as a final batch. If the signal channel is
+// closed before the input channel, values keep being accumulated and are
+// emitted in the final batch.
+//
+// The output channel is unbuffered.
+//
+// This is a non-blocking function: it launches a goroutine and returns the
+// channel for consumption. In order to stop the inner goroutine, one can close
+// the input channel or cancel the provided context.
+//
+// The output channel is always closed on cancellation, even if the input
+// channel is never closed. Values accumulated at the time of cancellation are
+// discarded.
+func BufferUntil[T any](ctx context.Context, in <-chan T, signal <-chan struct{}) <-chan []T {
+	out := make(chan []T)
+	go func() {
+		defer close(out)
+		batch := []T{}
+		for {
+			select {
+			case v, ok := <-in:
+				if !ok {
+					if len(batch) > 0 {
+						trySend(ctx, out, batch)
+					}
+					return
+				}
+				batch = append(batch, v)
+			case _, ok := <-signal:
+				if !ok {
+					signal = nil
+					continue
+				}
+				if !trySend(ctx, out, batch) {
+					return
+				}
+				batch = []T{}
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return out
+}
+
+// ChunkOption is an option that can be provided to Chunk. See also WithClock.
+type ChunkOption interface {
+	applyChunk(*chunkOptions)
+}
+
+type chunkOptions struct {
+	timeOptions
+	flushInterval time.Duration
+}
+
+type chunkOption func(*chunkOptions)
+
+func (o chunkOption) applyChunk(opts *chunkOptions) {
+	o(opts)
+}
+
+// WithFlushInterval makes Chunk emit the current chunk once the provided
+// duration elapses since the first value of the chunk was received, even if
+// the chunk has fewer values than the chunk size. This bounds how long a value
+// can wait in a partial chunk.
+//
+// By default, chunks are only emitted when they're full or when the input
+// channel is closed. See WithClock for changing the clock used to wait for the
+// flush interval.
+func WithFlushInterval(d time.Duration) ChunkOption {
+	return chunkOption(func(opts *chunkOptions) {
+		opts.flushInterval = d
+	})
+}
+
+// Chunk takes an input channel and returns a channel that groups values from
+// the input channel in slices of the provided size. When the input channel is
+// closed, the remaining values, if any, are emitted as a final, smaller
+// chunk. If size is lower than 1, each value is emitted in its own chunk.
+//
+// The output channel is unbuffered.
+//
+// This is a non-blocking function: it launches a goroutine and returns the
+// channel for consumption. In order to stop the inner goroutine, one can close
+// the input channel or cancel the provided context.
+//
+// The output channel is always closed on cancellation, even if the input
+// channel is never closed. Values accumulated at the time of cancellation are
+// discarded.
+func Chunk[T any](ctx context.Context, in <-chan T, size int, opts ...ChunkOption) <-chan []T {
+	options := chunkOptions{timeOptions: defaultTimeOptions()}
+	for _, opt := range opts {
+		opt.applyChunk(&options)
+	}
+	if size < 1 {
+		size = 1
+	}
+
+	out := make(chan []T)
+	go func() {
+		defer close(out)
+		var chunk []T
+		timer := lazyTimer{clock: options.clock}
+		defer timer.Stop()
+		flush := func() bool {
+			timer.Stop()
+			ok := trySend(ctx, out, chunk)
+			chunk = nil
+			return ok
+		}
+		for {
+			select {
+			case v, ok := <-in:
+				if !ok {
+					if len(chunk) > 0 {
+						flush()
+					}
+					return
+				}
+				if len(chunk) == 0 && options.flushInterval > 0 {
+					timer.Reset(options.flushInterval)
+				}
+				chunk = append(chunk, v)
+				if len(chunk) == size && !flush() {
+					return
+				}
+			case <-timer.C():
+				if !flush() {
+					return
+				}
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return out
+}
+
+// WindowBy takes an input channel and returns a channel that groups values
+// from the input channel in slices, emitting the current slice either when it
+// reaches maxSize values or when isBoundary returns true for a value, which is
+// included as the last value of its slice, whichever comes first. When the
+// input channel is closed, the remaining values, if any, are emitted as a
+// final slice. If maxSize is lower than 1, it's treated as 1.
+//
+// The output channel is unbuffered.
+//
+// This is a non-blocking function: it launches a goroutine and returns the
+// channel for consumption. In order to stop the inner goroutine, one can close
+// the input channel or cancel the provided context.
+//
+// The output channel is always closed on cancellation, even if the input
+// channel is never closed. Values accumulated at the time of cancellation are
+// discarded.
+func WindowBy[T any](ctx context.Context, in <-chan T, maxSize int, isBoundary func(T) bool) <-chan []T {
+	if maxSize < 1 {
+		maxSize = 1
+	}
+	out := make(chan []T)
+	go func() {
+		defer close(out)
+		var window []T
+		receiveLoop(ctx, in, func(v T) bool {
+			window = append(window, v)
+			if len(window) < maxSize && !isBoundary(v) {
+				return true
+			}
+			ok := trySend(ctx, out, window)
+			window = nil
+			return ok
+		})
+		if len(window) > 0 && ctx.Err() == nil {
+			trySend(ctx, out, window)
+		}
+	}()
+	return out
+}
+
+// FlattenSlices takes an input channel of slices and returns an output channel
+// that emits each element of each slice, in order. It's the inverse of Chunk.
+// Empty slices produce no values.
+//
+// The output channel is unbuffered.
+//
+// This is a non-blocking function: it launches a goroutine and returns the
+// channel for consumption. In order to stop the inner goroutine, one can close
+// the input channel or cancel the provided context.
+//
+// The output channel is always closed on cancellation, even if the input
+// channel is never closed. The remaining elements of the slice being sent at
+// the time of cancellation are discarded.
+func FlattenSlices[T any](ctx context.Context, in <-chan []T) <-chan T {
+	out := make(chan T)
+	go func() {
+		defer close(out)
+		receiveLoop(ctx, in, func(values []T) bool {
+			for _, v := range values {
+				if !trySend(ctx, out, v) {
+					return false
+				}
+			}
+			return true
+		})
+	}()
+	return out
+}
+
+// Replicate takes an input channel and returns an output channel that emits
+// each value from the input channel n consecutive times. If n is 1, all values
+// are emitted unmodified, and if n is lower than 1, all values are discarded.
+//
+// The capacity of the output channel will be same as the capacity of the
+// input channel.
+//
+// This is a non-blocking function: it launches a goroutine and returns the
+// channel for consumption. In order to stop the inner goroutine, one can close
+// the input channel or cancel the provided context.
+//
+// The output channel is always closed on cancellation, even if the input
+// channel is never closed.
+func Replicate[T any](ctx context.Context, in <-chan T, n int) <-chan T {
+	out := make(chan T, cap(in))
+	go func() {
+		defer close(out)
+		receiveLoop(ctx, in, func(v T) bool {
+			for i := 0; i < n; i++ {
+				if !trySend(ctx, out, v) {
+					return false
+				}
+			}
+			return true
+		})
+	}()
+	return out
+}
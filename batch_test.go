@@ -0,0 +1,253 @@
+package channels
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestBufferUntil(t *testing.T) {
+	t.Parallel()
+	in := make(chan int)
+	signal := make(chan struct{})
+	batches := BufferUntil(context.TODO(), in, signal)
+
+	in <- 1
+	in <- 2
+	signal <- struct{}{}
+	if got, expected := <-batches, []int{1, 2}; !reflect.DeepEqual(got, expected) {
+		t.Errorf("wrong batch returned\nwant %#v\ngot  %#v", expected, got)
+	}
+
+	signal <- struct{}{}
+	if got := <-batches; len(got) != 0 {
+		t.Errorf("unexpected non-empty batch: %#v", got)
+	}
+
+	in <- 3
+	close(in)
+	if got, expected := ToSlice(context.TODO(), batches), [][]int{{3}}; !reflect.DeepEqual(got, expected) {
+		t.Errorf("wrong final batches returned\nwant %#v\ngot  %#v", expected, got)
+	}
+}
+
+func TestBufferUntilClosedSignal(t *testing.T) {
+	t.Parallel()
+	in := make(chan int)
+	signal := make(chan struct{})
+	batches := BufferUntil(context.TODO(), in, signal)
+
+	in <- 1
+	close(signal)
+	in <- 2
+	in <- 3
+	close(in)
+	if got, expected := ToSlice(context.TODO(), batches), [][]int{{1, 2, 3}}; !reflect.DeepEqual(got, expected) {
+		t.Errorf("wrong batches returned\nwant %#v\ngot  %#v", expected, got)
+	}
+}
+
+func TestBufferUntilEmptyInput(t *testing.T) {
+	t.Parallel()
+	in := make(chan int)
+	close(in)
+	batches := ToSlice(context.TODO(), BufferUntil(context.TODO(), in, make(chan struct{})))
+	if batches != nil {
+		t.Errorf("unexpected non-nil slice: %#v", batches)
+	}
+}
+
+func TestBufferUntilWithContextCancellation(t *testing.T) {
+	t.Parallel()
+	ch := startGenerator(t, 0, func(p int) (int, bool) {
+		return p + 1, true
+	}, func() { time.Sleep(time.Second) })
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	batches := ToSlice(context.TODO(), BufferUntil(ctx, ch, make(chan struct{})))
+	if batches != nil {
+		t.Errorf("unexpected non-nil slice: %#v", batches)
+	}
+}
+
+func TestChunk(t *testing.T) {
+	t.Parallel()
+	ch := startGenerator(t, 0, func(p int) (int, bool) {
+		if p > 6 {
+			return p, false
+		}
+		return p + 1, true
+	}, nil)
+
+	chunks := ToSlice(context.TODO(), Chunk(context.TODO(), ch, 3))
+	expected := [][]int{{1, 2, 3}, {4, 5, 6}, {7}}
+	if !reflect.DeepEqual(chunks, expected) {
+		t.Errorf("wrong chunks returned\nwant %#v\ngot  %#v", expected, chunks)
+	}
+}
+
+func TestChunkWithFlushInterval(t *testing.T) {
+	t.Parallel()
+	in := make(chan int)
+	timers := make(chan chan time.Time, 10)
+	after := func(time.Duration) <-chan time.Time {
+		timer := make(chan time.Time, 1)
+		timers <- timer
+		return timer
+	}
+	out := Chunk(context.TODO(), in, 3, WithFlushInterval(time.Second), WithClock(testClock{after: after}))
+
+	in <- 1
+	timer := <-timers
+	in <- 2
+	timer <- time.Now()
+	if got, expected := <-out, []int{1, 2}; !reflect.DeepEqual(got, expected) {
+		t.Errorf("wrong chunk returned on timeout\nwant %#v\ngot  %#v", expected, got)
+	}
+
+	in <- 3
+	<-timers
+	in <- 4
+	in <- 5
+	if got, expected := <-out, []int{3, 4, 5}; !reflect.DeepEqual(got, expected) {
+		t.Errorf("wrong full chunk returned\nwant %#v\ngot  %#v", expected, got)
+	}
+
+	in <- 6
+	<-timers
+	close(in)
+	if got, expected := ToSlice(context.TODO(), out), [][]int{{6}}; !reflect.DeepEqual(got, expected) {
+		t.Errorf("wrong final chunks returned\nwant %#v\ngot  %#v", expected, got)
+	}
+}
+
+func TestChunkWithContextCancellation(t *testing.T) {
+	t.Parallel()
+	ch := startGenerator(t, 0, func(p int) (int, bool) {
+		return p + 1, true
+	}, func() { time.Sleep(10 * time.Millisecond) })
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	chunks := ToSlice(context.TODO(), Chunk(ctx, ch, 1000))
+	if chunks != nil {
+		t.Errorf("unexpected non-nil slice: %#v", chunks)
+	}
+}
+
+func TestWindowBy(t *testing.T) {
+	t.Parallel()
+	isZero := func(v int) bool { return v == 0 }
+	tests := []struct {
+		name     string
+		values   []int
+		maxSize  int
+		expected [][]int
+	}{
+		{name: "empty input", maxSize: 3},
+		{name: "size only", values: []int{1, 2, 3, 4, 5, 6, 7}, maxSize: 3, expected: [][]int{{1, 2, 3}, {4, 5, 6}, {7}}},
+		{name: "boundary before max size", values: []int{1, 0, 2, 3, 4, 5, 0}, maxSize: 3, expected: [][]int{{1, 0}, {2, 3, 4}, {5, 0}}},
+		{name: "boundary at max size", values: []int{1, 2, 0, 3}, maxSize: 3, expected: [][]int{{1, 2, 0}, {3}}},
+		{name: "consecutive boundaries", values: []int{0, 0, 1}, maxSize: 3, expected: [][]int{{0}, {0}, {1}}},
+		{name: "max size lower than 1", values: []int{1, 2}, maxSize: 0, expected: [][]int{{1}, {2}}},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+			windows := ToSlice(context.TODO(), WindowBy(context.TODO(), fromSlice(t, test.values), test.maxSize, isZero))
+			if !reflect.DeepEqual(windows, test.expected) {
+				t.Errorf("wrong windows returned\nwant %#v\ngot  %#v", test.expected, windows)
+			}
+		})
+	}
+}
+
+func TestWindowByWithContextCancellation(t *testing.T) {
+	t.Parallel()
+	ch := startGenerator(t, 0, func(p int) (int, bool) {
+		return p + 1, true
+	}, func() { time.Sleep(10 * time.Millisecond) })
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	windows := ToSlice(context.TODO(), WindowBy(ctx, ch, 1000, func(int) bool { return false }))
+	if windows != nil {
+		t.Errorf("unexpected non-nil slice: %#v", windows)
+	}
+}
+
+func TestFlattenSlices(t *testing.T) {
+	t.Parallel()
+	ch := fromSlice(t, [][]int{{1, 2, 3}, {}, {4}, nil, {5, 6}})
+
+	values := ToSlice(context.TODO(), FlattenSlices(context.TODO(), ch))
+	expected := []int{1, 2, 3, 4, 5, 6}
+	if !reflect.DeepEqual(values, expected) {
+		t.Errorf("wrong values returned\nwant %#v\ngot  %#v", expected, values)
+	}
+}
+
+func TestFlattenSlicesWithContextCancellation(t *testing.T) {
+	t.Parallel()
+	ch := fromSlice(t, [][]int{{1, 2, 3, 4, 5, 6}, {7, 8}})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	out := FlattenSlices(ctx, ch)
+	if v := <-out; v != 1 {
+		t.Fatalf("wrong first value\nwant 1\ngot  %d", v)
+	}
+	cancel()
+
+	// wait for the inner goroutine to observe the cancellation.
+	time.Sleep(10 * time.Millisecond)
+	if values := ToSlice(context.TODO(), out); values != nil {
+		t.Errorf("unexpected non-nil slice: %#v", values)
+	}
+}
+
+func TestReplicate(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name     string
+		n        int
+		expected []int
+	}{
+		{name: "zero", n: 0},
+		{name: "negative", n: -1},
+		{name: "one", n: 1, expected: []int{1, 2}},
+		{name: "three", n: 3, expected: []int{1, 1, 1, 2, 2, 2}},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+			values := ToSlice(context.TODO(), Replicate(context.TODO(), fromSlice(t, []int{1, 2}), test.n))
+			if !reflect.DeepEqual(values, test.expected) {
+				t.Errorf("wrong values returned\nwant %#v\ngot  %#v", test.expected, values)
+			}
+		})
+	}
+}
+
+func TestReplicateWithContextCancellation(t *testing.T) {
+	t.Parallel()
+	ch := fromSlice(t, []int{1, 2})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	out := Replicate(ctx, ch, 1000)
+	if v := <-out; v != 1 {
+		t.Fatalf("wrong first value\nwant 1\ngot  %d", v)
+	}
+	cancel()
+
+	// wait for the inner goroutine to observe the cancellation.
+	time.Sleep(10 * time.Millisecond)
+	if values := ToSlice(context.TODO(), out); values != nil {
+		t.Errorf("unexpected non-nil slice: %#v", values)
+	}
+}
@@ -0,0 +1,45 @@
+package channels
+
+import "context"
+
+// Bisect takes an input channel and splits it in two at the first value for
+// which the provided boundary function returns true: the before channel emits
+// all values up to, and excluding, that value, and the after channel emits
+// that value and every value after it.
+//
+// Both output channels are fed by a single goroutine, in order: values are
+// only sent to after once before is closed, so consumers must read before
+// until it's closed and only then read after. Reading after while before
+// still has values blocks until before is drained.
+//
+// The capacity of both output channels will be cap(inputChannel).
+//
+// This is a non-blocking function: it launches a goroutine and returns the
+// channels for consumption. In order to stop the inner goroutine, one can
+// close the input channel or cancel the provided context.
+//
+// Both output channels are always closed on cancellation, even if the input
+// channel is never closed. If the input channel is closed before the boundary
+// is found, after is closed without emitting any values.
+func Bisect[T any](ctx context.Context, in <-chan T, boundary func(T) bool) (before <-chan T, after <-chan T) {
+	beforeCh := make(chan T, cap(in))
+	afterCh := make(chan T, cap(in))
+	go func() {
+		defer close(afterCh)
+		found := false
+		receiveLoop(ctx, in, func(v T) bool {
+			if !found && boundary(v) {
+				found = true
+				close(beforeCh)
+			}
+			if found {
+				return trySend(ctx, afterCh, v)
+			}
+			return trySend(ctx, beforeCh, v)
+		})
+		if !found {
+			close(beforeCh)
+		}
+	}()
+	return beforeCh, afterCh
+}
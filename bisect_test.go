@@ -0,0 +1,68 @@
+package channels
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestBisect(t *testing.T) {
+	t.Parallel()
+	in := fromSlice(t, []int{3, 1, 4, -1, 5, -9, 2})
+	before, after := Bisect(context.TODO(), in, func(v int) bool { return v < 0 })
+
+	expectedBefore := []int{3, 1, 4}
+	if got := ToSlice(context.TODO(), before); !reflect.DeepEqual(got, expectedBefore) {
+		t.Errorf("wrong values returned in before\nwant %#v\ngot  %#v", expectedBefore, got)
+	}
+	expectedAfter := []int{-1, 5, -9, 2}
+	if got := ToSlice(context.TODO(), after); !reflect.DeepEqual(got, expectedAfter) {
+		t.Errorf("wrong values returned in after\nwant %#v\ngot  %#v", expectedAfter, got)
+	}
+}
+
+func TestBisectBoundaryNotFound(t *testing.T) {
+	t.Parallel()
+	in := fromSlice(t, []int{3, 1, 4})
+	before, after := Bisect(context.TODO(), in, func(v int) bool { return v < 0 })
+
+	expectedBefore := []int{3, 1, 4}
+	if got := ToSlice(context.TODO(), before); !reflect.DeepEqual(got, expectedBefore) {
+		t.Errorf("wrong values returned in before\nwant %#v\ngot  %#v", expectedBefore, got)
+	}
+	if got := ToSlice(context.TODO(), after); got != nil {
+		t.Errorf("unexpected non-nil slice in after: %#v", got)
+	}
+}
+
+func TestBisectBoundaryFirst(t *testing.T) {
+	t.Parallel()
+	in := fromSlice(t, []int{-3, 1, 4})
+	before, after := Bisect(context.TODO(), in, func(v int) bool { return v < 0 })
+
+	if got := ToSlice(context.TODO(), before); got != nil {
+		t.Errorf("unexpected non-nil slice in before: %#v", got)
+	}
+	expectedAfter := []int{-3, 1, 4}
+	if got := ToSlice(context.TODO(), after); !reflect.DeepEqual(got, expectedAfter) {
+		t.Errorf("wrong values returned in after\nwant %#v\ngot  %#v", expectedAfter, got)
+	}
+}
+
+func TestBisectWithContextCancellation(t *testing.T) {
+	t.Parallel()
+	ch := startGenerator(t, 0, func(p int) (int, bool) {
+		return p + 1, true
+	}, func() { time.Sleep(time.Second) })
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	before, after := Bisect(ctx, ch, func(v int) bool { return v < 0 })
+	if got := ToSlice(context.TODO(), before); got != nil {
+		t.Errorf("unexpected non-nil slice in before: %#v", got)
+	}
+	if got := ToSlice(context.TODO(), after); got != nil {
+		t.Errorf("unexpected non-nil slice in after: %#v", got)
+	}
+}
@@ -0,0 +1,29 @@
+//go:build go1.20
+
+package channels
+
+import "context"
+
+// ToSliceCause converts the provided channel to a slice, like ToSlice. If the
+// conversion is aborted by the cancellation of the provided context, it also
+// returns the cause of the cancellation, as reported by context.Cause, so
+// consumers can tell why the stream ended. If the input channel is closed, the
+// returned error is nil.
+//
+// This is a blocking function that can be aborted via the provided context or
+// by closing the input channel. On cancellation, it returns the values
+// received so far.
+func ToSliceCause[T any](ctx context.Context, in <-chan T) ([]T, error) {
+	var result []T
+	for {
+		select {
+		case v, ok := <-in:
+			if !ok {
+				return result, nil
+			}
+			result = append(result, v)
+		case <-ctx.Done():
+			return result, context.Cause(ctx)
+		}
+	}
+}
@@ -0,0 +1,41 @@
+//go:build go1.20
+
+package channels
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestToSliceCause(t *testing.T) {
+	t.Parallel()
+	values, err := ToSliceCause(context.TODO(), fromSlice(t, []int{1, 2, 3}))
+	expected := []int{1, 2, 3}
+	if !reflect.DeepEqual(values, expected) {
+		t.Errorf("wrong values returned\nwant %#v\ngot  %#v", expected, values)
+	}
+	if err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestToSliceCauseWithContextCancellation(t *testing.T) {
+	t.Parallel()
+	ch := startGenerator(t, 0, func(p int) (int, bool) {
+		return p + 1, true
+	}, func() { time.Sleep(10 * time.Millisecond) })
+
+	errShutdown := errors.New("shutting down")
+	ctx, cancel := context.WithCancelCause(context.Background())
+	time.AfterFunc(100*time.Millisecond, func() { cancel(errShutdown) })
+	values, err := ToSliceCause(ctx, ch)
+	if len(values) == 0 {
+		t.Fatal("unexpected empty slice")
+	}
+	if err != errShutdown {
+		t.Errorf("wrong error returned\nwant %v\ngot  %v", errShutdown, err)
+	}
+}
@@ -1,6 +1,9 @@
 package channels
 
-import "context"
+import (
+	"context"
+	"sync"
+)
 
 // ToSlice converts the provided channel to a slice.
 //
@@ -28,20 +31,189 @@ func ToSlice[T any](ctx context.Context, in <-chan T) []T {
 //
 // The output channel is always closed on cancellation, even if the input
 // channel is never closed.
-func Filter[T any](ctx context.Context, in <-chan T, predicate func(T) bool) <-chan T {
-	out := make(chan T, cap(in))
+//
+// See WithOutputCap for changing the capacity of the output channel and
+// WithBufferGauge for observing its occupancy.
+func Filter[T any](ctx context.Context, in <-chan T, predicate func(T) bool, opts ...OperatorOption) <-chan T {
+	options := newOperatorOptions(opts)
+	out := make(chan T, options.capacity(cap(in)))
 	go func() {
 		receiveLoop(ctx, in, func(v T) bool {
 			if predicate(v) {
-				return trySend(ctx, out, v)
+				return sendObserved(ctx, out, v, options)
+			}
+			return true
+		})
+		close(out)
+	}()
+	return out
+}
+
+// FilterError is like Filter, but the predicate function can fail. It returns
+// two channels: one with the values for which the predicate returns true and
+// no error, and another one with the errors returned by the predicate. Values
+// for which the predicate returns an error are discarded, unless
+// WithKeepOnError is provided, in which case they're sent to the output
+// channel after the error is sent to the error channel.
+//
+// Both channels are fed by a single goroutine, so the error channel must be
+// consumed along with the output channel, as described in MapError.
+//
+// The capacity of the output channel will be same as the capacity of the input
+// channel. The capacity of the error channel will be 0, unless WithErrorBuffer
+// is provided.
+//
+// This is a non-blocking function: it launches a goroutine and returns the
+// channels for consumption. In order to stop the inner goroutine, one can
+// close the input channel or cancel the provided context.
+//
+// The output and error channels are always closed on cancellation, even if
+// the input channel is never closed.
+//
+// See WithOutputCap for changing the capacity of the output channel and
+// WithBufferGauge for observing its occupancy.
+func FilterError[T any](ctx context.Context, in <-chan T, predicate func(T) (bool, error), opts ...FilterErrorOption) (<-chan T, <-chan error) {
+	options := newOperatorOptions(opts)
+	out := make(chan T, options.capacity(cap(in)))
+	errs := make(chan error, options.errorBuffer)
+	go func() {
+		defer close(out)
+		defer close(errs)
+		receiveLoop(ctx, in, func(v T) bool {
+			keep, err := predicate(v)
+			if err != nil {
+				if !trySend(ctx, errs, err) {
+					return false
+				}
+				keep = options.keepOnError
+			}
+			if keep {
+				return sendObserved(ctx, out, v, options)
 			}
 			return true
 		})
+	}()
+	return out, errs
+}
+
+// FilterParallel is like Filter, but evaluates the predicate function
+// concurrently with the given number of workers, preserving the order of the
+// input channel in the output channel. If workers is lower than 1, a single
+// worker is used. See FlatMapParallel for how values that complete out of
+// order are buffered.
+//
+// The capacity of the output channel will be same as the capacity of the input
+// channel.
+//
+// This is a non-blocking function: it launches goroutines and returns the
+// channel for consumption. In order to stop the inner goroutines, one can
+// close the input channel or cancel the provided context.
+//
+// The output channel is always closed on cancellation, even if the input
+// channel is never closed.
+func FilterParallel[T any](ctx context.Context, in <-chan T, workers int, predicate func(T) bool) <-chan T {
+	return FlatMapParallel(ctx, in, workers, func(v T) []T {
+		if predicate(v) {
+			return []T{v}
+		}
+		return nil
+	})
+}
+
+// FilterParallelUnordered is like FilterParallel, but doesn't preserve the
+// order of the input channel: values are sent to the output channel as soon as
+// the predicate function returns.
+//
+// The capacity of the output channel will be same as the capacity of the input
+// channel.
+//
+// This is a non-blocking function: it launches goroutines and returns the
+// channel for consumption. In order to stop the inner goroutines, one can
+// close the input channel or cancel the provided context.
+//
+// The output channel is always closed on cancellation, even if the input
+// channel is never closed. In both cases, the output channel is only closed
+// after all workers have returned.
+func FilterParallelUnordered[T any](ctx context.Context, in <-chan T, workers int, predicate func(T) bool) <-chan T {
+	if workers < 1 {
+		workers = 1
+	}
+	out := make(chan T, cap(in))
+	var wg sync.WaitGroup
+	wg.Add(workers)
+	for i := 0; i < workers; i++ {
+		go func() {
+			defer wg.Done()
+			receiveLoop(ctx, in, func(v T) bool {
+				if predicate(v) {
+					return trySend(ctx, out, v)
+				}
+				return true
+			})
+		}()
+	}
+	go func() {
+		wg.Wait()
 		close(out)
 	}()
 	return out
 }
 
+// Consume reads values from the input channel and calls f with each of them.
+// It's the building block used by the operators in this package, and can be
+// used to implement custom consumers.
+//
+// This is a blocking function that returns when the provided context is
+// cancelled, when the input channel is closed or when f returns false.
+func Consume[T any](ctx context.Context, in <-chan T, f func(T) bool) {
+	receiveLoop(ctx, in, f)
+}
+
+// Into forwards all values from the input channel to the provided output
+// channel, which is owned by the caller. This allows operators to feed
+// channels that are created and managed elsewhere. The output channel is
+// never closed by Into, as other senders may still use it.
+//
+// This is a blocking function that returns when the provided context is
+// cancelled or when the input channel is closed.
+func Into[T any](ctx context.Context, in <-chan T, out chan<- T) {
+	receiveLoop(ctx, in, func(v T) bool {
+		return trySend(ctx, out, v)
+	})
+}
+
+// WaitClosed drains the provided channels concurrently, discarding their
+// values, and returns once all of them are closed. This is useful to wait for
+// several pipeline stages to finish.
+//
+// This is a blocking function that returns when all the channels are closed
+// or when the provided context is cancelled.
+func WaitClosed[T any](ctx context.Context, chans ...<-chan T) {
+	var wg sync.WaitGroup
+	wg.Add(len(chans))
+	for _, ch := range chans {
+		go func(ch <-chan T) {
+			defer wg.Done()
+			receiveLoop(ctx, ch, func(T) bool {
+				return true
+			})
+		}(ch)
+	}
+	wg.Wait()
+}
+
+// Send sends v to the provided channel, blocking until either the value is
+// sent or the context is cancelled. It returns true if the value was sent and
+// false if the context was cancelled before that.
+//
+// If the channel is ready to receive and the context is already cancelled, the
+// outcome is nondeterministic: the value may or may not be sent. Callers that
+// need cancellation to take precedence should check ctx.Err() before calling
+// Send.
+func Send[T any](ctx context.Context, ch chan<- T, v T) bool {
+	return trySend(ctx, ch, v)
+}
+
 func min(x, y int) int {
 	if x < y {
 		return x
@@ -74,3 +246,51 @@ func trySend[T any](ctx context.Context, ch chan<- T, v T) bool {
 		return false
 	}
 }
+
+// buffer returns a channel that emits all values from the input channel, in
+// order, holding them in an unbounded buffer so that sends to the input channel
+// never wait on receives from the returned channel.
+func buffer[T any](ctx context.Context, in <-chan T) <-chan T {
+	out := make(chan T)
+	go func() {
+		defer close(out)
+		var queue []T
+		for in != nil || len(queue) > 0 {
+			var (
+				sendCh chan<- T
+				next   T
+			)
+			if len(queue) > 0 {
+				sendCh = out
+				next = queue[0]
+			}
+			select {
+			case v, ok := <-in:
+				if !ok {
+					in = nil
+					continue
+				}
+				queue = append(queue, v)
+			case sendCh <- next:
+				var zero T
+				queue[0] = zero
+				queue = queue[1:]
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return out
+}
+
+// tryReceive receives a value from the channel, returning false if the channel
+// is closed or the context is cancelled before a value is received.
+func tryReceive[T any](ctx context.Context, ch <-chan T) (T, bool) {
+	select {
+	case v, ok := <-ch:
+		return v, ok
+	case <-ctx.Done():
+		var zero T
+		return zero, false
+	}
+}
@@ -2,7 +2,9 @@ package channels
 
 import (
 	"context"
+	"fmt"
 	"reflect"
+	"sort"
 	"testing"
 	"time"
 )
@@ -87,6 +89,134 @@ func TestFilterWithContextCancellation(t *testing.T) {
 	}
 }
 
+func TestFilterError(t *testing.T) {
+	t.Parallel()
+	predicate := func(v int) (bool, error) {
+		if v%3 == 0 {
+			return false, fmt.Errorf("%d is a multiple of 3", v)
+		}
+		return v%2 == 0, nil
+	}
+	expectedErrs := []string{"3 is a multiple of 3", "6 is a multiple of 3", "9 is a multiple of 3"}
+	tests := []struct {
+		name         string
+		opts         []FilterErrorOption
+		expectedVals []int
+	}{
+		{name: "drops values on error", expectedVals: []int{2, 4, 8, 10}},
+		{name: "keeps values on error", opts: []FilterErrorOption{WithKeepOnError()}, expectedVals: []int{2, 3, 4, 6, 8, 9, 10}},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+			ch := fromSlice(t, []int{1, 2, 3, 4, 5, 6, 7, 8, 9, 10})
+			out, errs := FilterError(context.TODO(), ch, predicate, test.opts...)
+			gotVals, gotErrs := collectBoth(out, Map(context.TODO(), errs, func(err error) string { return err.Error() }))
+
+			if !reflect.DeepEqual(gotVals, test.expectedVals) {
+				t.Errorf("wrong values returned\nwant %#v\ngot  %#v", test.expectedVals, gotVals)
+			}
+			if !reflect.DeepEqual(gotErrs, expectedErrs) {
+				t.Errorf("wrong errors returned\nwant %#v\ngot  %#v", expectedErrs, gotErrs)
+			}
+		})
+	}
+}
+
+func TestFilterErrorWithContextCancellation(t *testing.T) {
+	t.Parallel()
+	ch := startGenerator(t, 0, func(p int) (int, bool) {
+		return p + 1, true
+	}, func() { time.Sleep(time.Second) })
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	out, errs := FilterError(ctx, ch, func(int) (bool, error) {
+		return true, nil
+	})
+	gotVals, gotErrs := collectBoth(out, errs)
+	if gotVals != nil || gotErrs != nil {
+		t.Errorf("unexpected non-nil slices: %#v, %#v", gotVals, gotErrs)
+	}
+}
+
+func TestFilterParallel(t *testing.T) {
+	t.Parallel()
+	ch := startGenerator(t, 0, func(p int) (int, bool) {
+		if p > 19 {
+			return p, false
+		}
+		return p + 1, true
+	}, nil)
+
+	evens := FilterParallel(context.TODO(), ch, 4, func(v int) bool {
+		time.Sleep(time.Duration(20-v) * time.Millisecond)
+		return v%2 == 0
+	})
+
+	expected := []int{2, 4, 6, 8, 10, 12, 14, 16, 18, 20}
+	got := ToSlice(context.TODO(), evens)
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("wrong values returned\nwant %#v\ngot  %#v", expected, got)
+	}
+}
+
+func TestFilterParallelWithContextCancellation(t *testing.T) {
+	t.Parallel()
+	ch := startGenerator(t, 0, func(i int) (int, bool) {
+		return i + 1, true
+	}, nil)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	evens := FilterParallel(ctx, ch, 4, func(v int) bool { return v%2 == 0 })
+
+	got := ToSlice(context.TODO(), evens)
+	if len(got) == 0 {
+		t.Fatal("unexpected empty slice")
+	}
+}
+
+func TestFilterParallelUnordered(t *testing.T) {
+	t.Parallel()
+	ch := startGenerator(t, 0, func(p int) (int, bool) {
+		if p > 19 {
+			return p, false
+		}
+		return p + 1, true
+	}, nil)
+
+	evens := FilterParallelUnordered(context.TODO(), ch, 4, func(v int) bool {
+		time.Sleep(time.Duration(20-v) * time.Millisecond)
+		return v%2 == 0
+	})
+
+	got := ToSlice(context.TODO(), evens)
+	sort.Ints(got)
+	expected := []int{2, 4, 6, 8, 10, 12, 14, 16, 18, 20}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("wrong values returned\nwant %#v\ngot  %#v", expected, got)
+	}
+}
+
+func TestFilterParallelUnorderedWithContextCancellation(t *testing.T) {
+	t.Parallel()
+	ch := startGenerator(t, 0, func(i int) (int, bool) {
+		return i + 1, true
+	}, nil)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	evens := FilterParallelUnordered(ctx, ch, 4, func(v int) bool { return v%2 == 0 })
+
+	got := ToSlice(context.TODO(), evens)
+	if len(got) == 0 {
+		t.Fatal("unexpected empty slice")
+	}
+}
+
 func startGenerator[T any](t *testing.T, init T, gen func(prev T) (T, bool), cb func()) <-chan T {
 	t.Helper()
 	abort := make(chan struct{})
@@ -115,3 +245,185 @@ func startGenerator[T any](t *testing.T, init T, gen func(prev T) (T, bool), cb
 	}()
 	return ch
 }
+
+func TestConsume(t *testing.T) {
+	t.Parallel()
+	ch := startGenerator(t, 0, func(p int) (int, bool) {
+		if p > 4 {
+			return p, false
+		}
+		return p + 1, true
+	}, nil)
+
+	var values []int
+	Consume(context.TODO(), ch, func(v int) bool {
+		values = append(values, v)
+		return true
+	})
+	expectedSlice := []int{1, 2, 3, 4, 5}
+	if !reflect.DeepEqual(values, expectedSlice) {
+		t.Errorf("wrong values returned\nwant %#v\ngot  %#v", expectedSlice, values)
+	}
+}
+
+func TestConsumeStopsWhenFunctionReturnsFalse(t *testing.T) {
+	t.Parallel()
+	ch := startGenerator(t, 0, func(p int) (int, bool) {
+		return p + 1, true
+	}, nil)
+
+	var values []int
+	Consume(context.TODO(), ch, func(v int) bool {
+		values = append(values, v)
+		return v < 3
+	})
+	expectedSlice := []int{1, 2, 3}
+	if !reflect.DeepEqual(values, expectedSlice) {
+		t.Errorf("wrong values returned\nwant %#v\ngot  %#v", expectedSlice, values)
+	}
+
+	next := <-ch
+	if next != 4 {
+		t.Errorf("wrong next value in the input channel\nwant 4\ngot  %d", next)
+	}
+}
+
+func TestConsumeWithContextCancellation(t *testing.T) {
+	t.Parallel()
+	ch := startGenerator(t, "", func(v string) (string, bool) {
+		return v, true
+	}, func() { time.Sleep(time.Second) })
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	var values []string
+	Consume(ctx, ch, func(v string) bool {
+		values = append(values, v)
+		return true
+	})
+	if values != nil {
+		t.Errorf("unexpected non-nil slice: %#v", values)
+	}
+}
+
+func TestInto(t *testing.T) {
+	t.Parallel()
+	out := make(chan int, 5)
+	Into(context.TODO(), fromSlice(t, []int{1, 2, 3}), out)
+	out <- 4
+	close(out)
+
+	values := ToSlice(context.TODO(), out)
+	expected := []int{1, 2, 3, 4}
+	if !reflect.DeepEqual(values, expected) {
+		t.Errorf("wrong values returned\nwant %#v\ngot  %#v", expected, values)
+	}
+}
+
+func TestIntoWithContextCancellation(t *testing.T) {
+	t.Parallel()
+	ch := startGenerator(t, 0, func(p int) (int, bool) {
+		return p + 1, true
+	}, func() { time.Sleep(10 * time.Millisecond) })
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	out := make(chan int)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		Into(ctx, ch, out)
+	}()
+
+	var values []int
+	for {
+		select {
+		case v := <-out:
+			values = append(values, v)
+			continue
+		case <-done:
+		}
+		break
+	}
+	if len(values) == 0 {
+		t.Fatal("unexpected empty slice")
+	}
+	for i, v := range values {
+		if v != i+1 {
+			t.Fatalf("wrong values returned: %#v", values)
+		}
+	}
+	select {
+	case v, ok := <-out:
+		t.Errorf("unexpected receive from the output channel after cancellation: %d, %t", v, ok)
+	default:
+	}
+}
+
+func TestWaitClosed(t *testing.T) {
+	t.Parallel()
+	chans := make([]<-chan int, 3)
+	for i := range chans {
+		ch := make(chan int)
+		chans[i] = ch
+		go func(delay time.Duration) {
+			defer close(ch)
+			ch <- 1
+			time.Sleep(delay)
+			ch <- 2
+		}(time.Duration(i+1) * 20 * time.Millisecond)
+	}
+
+	start := time.Now()
+	WaitClosed(context.TODO(), chans...)
+	if elapsed := time.Since(start); elapsed < 60*time.Millisecond {
+		t.Errorf("returned before the last channel was closed, after %s", elapsed)
+	}
+	for i, ch := range chans {
+		if v, ok := <-ch; ok {
+			t.Errorf("channel %d wasn't drained, got %d", i, v)
+		}
+	}
+}
+
+func TestWaitClosedWithContextCancellation(t *testing.T) {
+	t.Parallel()
+	ch := startGenerator(t, 0, func(p int) (int, bool) {
+		return p + 1, true
+	}, func() { time.Sleep(10 * time.Millisecond) })
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		WaitClosed(ctx, ch, make(chan int))
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("didn't return after the context was cancelled")
+	}
+}
+
+func TestSend(t *testing.T) {
+	t.Parallel()
+	ch := make(chan int, 1)
+	if !Send(context.TODO(), ch, 42) {
+		t.Fatal("unexpected false return from Send")
+	}
+	if v := <-ch; v != 42 {
+		t.Errorf("wrong value sent\nwant 42\ngot  %d", v)
+	}
+}
+
+func TestSendWithCancelledContext(t *testing.T) {
+	t.Parallel()
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	ch := make(chan int)
+	if Send(ctx, ch, 42) {
+		t.Error("unexpected true return from Send with cancelled context")
+	}
+}
@@ -0,0 +1,61 @@
+package channels
+
+import "context"
+
+// Checkpoint takes an input channel and returns two channels: one that emits
+// all the values from the input channel, and another one with errors. Every
+// every values, after the value is sent to the output channel, the save
+// function is invoked with the zero-based index of the value in the input
+// channel and the value itself, so that the progress of the pipeline can be
+// recorded and resumed later. Errors returned by the save function are sent
+// to the error channel instead of stopping the operator. If every is lower
+// than 1, the save function is invoked for every value.
+//
+// Both channels are fed by a single goroutine, so, as with MapError, the
+// error channel must be consumed along with the output channel: a consumer
+// that only reads the output channel blocks the goroutine on the first save
+// error. Consumers should read both channels concurrently until they're
+// closed, or buffer errors with WithErrorBuffer when the number of errors is
+// bounded.
+//
+// The capacity of the output channel will be same as the capacity of the input
+// channel. The capacity of the error channel will be 0, unless WithErrorBuffer
+// is provided.
+//
+// This is a non-blocking function: it launches a goroutine and returns the
+// channel for consumption. In order to stop the inner goroutine, one can close
+// the input channel or cancel the provided context.
+//
+// The output and errors channels are always closed on cancellation, even if
+// the input channel is never closed.
+//
+// See WithOutputCap for changing the capacity of the output channel and
+// WithBufferGauge for observing its occupancy.
+func Checkpoint[T any](ctx context.Context, in <-chan T, every int, save func(index int, v T) error, opts ...ErrorOption) (<-chan T, <-chan error) {
+	options := newOperatorOptions(opts)
+	if every < 1 {
+		every = 1
+	}
+	out := make(chan T, options.capacity(cap(in)))
+	errs := make(chan error, options.errorBuffer)
+	go func() {
+		index := 0
+		receiveLoop(ctx, in, func(v T) bool {
+			if !sendObserved(ctx, out, v, options) {
+				return false
+			}
+			i := index
+			index++
+			if index%every != 0 {
+				return true
+			}
+			if err := save(i, v); err != nil {
+				return trySend(ctx, errs, err)
+			}
+			return true
+		})
+		close(out)
+		close(errs)
+	}()
+	return out, errs
+}
@@ -0,0 +1,78 @@
+package channels
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestCheckpoint(t *testing.T) {
+	t.Parallel()
+	ch := fromSlice(t, []string{"a", "b", "c", "d", "e", "f", "g"})
+
+	type saved struct {
+		index int
+		value string
+	}
+	var checkpoints []saved
+	errSave := errors.New("failed to save")
+	values, errs := Checkpoint(context.TODO(), ch, 3, func(index int, v string) error {
+		checkpoints = append(checkpoints, saved{index, v})
+		if index == 2 {
+			return errSave
+		}
+		return nil
+	})
+	gotValues, gotErrs := collectValuesAndErrors(values, errs)
+
+	expectedValues := []string{"a", "b", "c", "d", "e", "f", "g"}
+	if !reflect.DeepEqual(gotValues, expectedValues) {
+		t.Errorf("wrong values returned\nwant %#v\ngot  %#v", expectedValues, gotValues)
+	}
+	expectedCheckpoints := []saved{{2, "c"}, {5, "f"}}
+	if !reflect.DeepEqual(checkpoints, expectedCheckpoints) {
+		t.Errorf("wrong checkpoints saved\nwant %#v\ngot  %#v", expectedCheckpoints, checkpoints)
+	}
+	expectedErrs := []error{errSave}
+	if !reflect.DeepEqual(gotErrs, expectedErrs) {
+		t.Errorf("wrong errors returned\nwant %#v\ngot  %#v", expectedErrs, gotErrs)
+	}
+}
+
+func TestCheckpointWithErrorBuffer(t *testing.T) {
+	t.Parallel()
+	ch := fromSlice(t, []int{1, 2, 3, 4, 5})
+	errSave := errors.New("failed to save")
+	out, errs := Checkpoint(context.TODO(), ch, 2, func(int, int) error {
+		return errSave
+	}, WithErrorBuffer(2))
+
+	// the save errors fit in the buffer, so the output channel can be
+	// consumed on its own.
+	values := ToSlice(context.TODO(), out)
+	expected := []int{1, 2, 3, 4, 5}
+	if !reflect.DeepEqual(values, expected) {
+		t.Errorf("wrong values returned\nwant %#v\ngot  %#v", expected, values)
+	}
+	expectedErrs := []error{errSave, errSave}
+	if gotErrs := ToSlice(context.TODO(), errs); !reflect.DeepEqual(gotErrs, expectedErrs) {
+		t.Errorf("wrong errors returned\nwant %#v\ngot  %#v", expectedErrs, gotErrs)
+	}
+}
+
+func TestCheckpointWithContextCancellation(t *testing.T) {
+	t.Parallel()
+	ch := startGenerator(t, 0, func(p int) (int, bool) {
+		return p + 1, true
+	}, func() { time.Sleep(time.Second) })
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	values, errs := Checkpoint(ctx, ch, 1, func(int, int) error { return nil })
+	gotValues, gotErrs := collectValuesAndErrors(values, errs)
+	if gotValues != nil || gotErrs != nil {
+		t.Errorf("unexpected non-nil slices: %#v, %#v", gotValues, gotErrs)
+	}
+}
@@ -0,0 +1,62 @@
+package channels
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// ErrCircuitOpen is the error sent by MapCircuitBreaker for values that are
+// rejected while the circuit is open.
+var ErrCircuitOpen = errors.New("channels: circuit open")
+
+// MapCircuitBreaker works like MapError, but it stops invoking the function
+// after threshold consecutive errors, opening the circuit. While the circuit
+// is open, the function is not invoked and ErrCircuitOpen is sent to the error
+// channel for every value received. Once cooldown elapses after the circuit
+// opens, the next value is used as a probe (the circuit is half-open): the
+// function is invoked with it, and if it succeeds the circuit is closed
+// again, otherwise the circuit opens for another cooldown. If threshold is
+// lower than 1, a single error opens the circuit.
+//
+// See WithClock for changing the clock used to read the current time.
+//
+// The capacity of the output channel will be same as the capacity of the input
+// channel. The capacity of the error channel will always be 0.
+//
+// This is a non-blocking function: it launches a goroutine and returns the
+// channel for consumption. In order to stop the inner goroutine, one can close
+// the input channel or cancel the provided context.
+//
+// The output and errors channels are always closed on cancellation, even if
+// the input channel is never closed.
+func MapCircuitBreaker[InputType, OutputType any](ctx context.Context, in <-chan InputType, f func(InputType) (OutputType, error), threshold int, cooldown time.Duration, opts ...TimeOption) (<-chan OutputType, <-chan error) {
+	if threshold < 1 {
+		threshold = 1
+	}
+	options := newTimeOptions(opts)
+	var (
+		failures int
+		open     bool
+		openedAt time.Time
+	)
+	return MapError(ctx, in, func(v InputType) (OutputType, error) {
+		if open {
+			if now := options.clock.Now(); now.Sub(openedAt) < cooldown {
+				var zero OutputType
+				return zero, ErrCircuitOpen
+			}
+		}
+		outValue, err := f(v)
+		if err != nil {
+			if failures++; open || failures >= threshold {
+				open = true
+				openedAt = options.clock.Now()
+			}
+			return outValue, err
+		}
+		failures = 0
+		open = false
+		return outValue, nil
+	})
+}
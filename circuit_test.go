@@ -0,0 +1,87 @@
+package channels
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestMapCircuitBreaker(t *testing.T) {
+	t.Parallel()
+	in := make(chan int)
+	defer close(in)
+
+	current := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	now := func() time.Time { return current }
+	errFailed := errors.New("failed")
+	var calls []int
+	out, errs := MapCircuitBreaker(context.TODO(), in, func(v int) (int, error) {
+		calls = append(calls, v)
+		if v < 0 {
+			return 0, errFailed
+		}
+		return v * 2, nil
+	}, 2, time.Minute, WithClock(testClock{now: now}))
+
+	// process sends the value and returns the output or the error produced
+	// for it. The output and the error channels are unbuffered, so the
+	// clock can be safely changed between calls.
+	process := func(v int) (int, error) {
+		in <- v
+		select {
+		case v := <-out:
+			return v, nil
+		case err := <-errs:
+			return 0, err
+		}
+	}
+
+	var tests = []struct {
+		name     string
+		advance  time.Duration
+		input    int
+		expected int
+		err      error
+	}{
+		{"closed success", 0, 1, 2, nil},
+		{"first failure", 0, -1, 0, errFailed},
+		{"success resets failures", 0, 2, 4, nil},
+		{"failure", 0, -1, 0, errFailed},
+		{"failure reaching threshold", 0, -1, 0, errFailed},
+		{"open", 0, 3, 0, ErrCircuitOpen},
+		{"still open", 59 * time.Second, 4, 0, ErrCircuitOpen},
+		{"failed probe", time.Second, -1, 0, errFailed},
+		{"reopened", 30 * time.Second, 5, 0, ErrCircuitOpen},
+		{"successful probe", 30 * time.Second, 6, 12, nil},
+		{"closed", 0, 7, 14, nil},
+	}
+	for _, test := range tests {
+		current = current.Add(test.advance)
+		got, err := process(test.input)
+		if got != test.expected || err != test.err {
+			t.Errorf("%s: wrong result\nwant %d, %v\ngot  %d, %v", test.name, test.expected, test.err, got, err)
+		}
+	}
+
+	expectedCalls := []int{1, -1, 2, -1, -1, -1, 6, 7}
+	if !reflect.DeepEqual(calls, expectedCalls) {
+		t.Errorf("wrong calls to the function\nwant %#v\ngot  %#v", expectedCalls, calls)
+	}
+}
+
+func TestMapCircuitBreakerWithContextCancellation(t *testing.T) {
+	t.Parallel()
+	ch := startGenerator(t, 0, func(p int) (int, bool) {
+		return p + 1, true
+	}, func() { time.Sleep(time.Second) })
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	out, errs := MapCircuitBreaker(ctx, ch, func(v int) (int, error) { return v, nil }, 1, time.Second)
+	values, gotErrs := collectValuesAndErrors(out, errs)
+	if values != nil || gotErrs != nil {
+		t.Errorf("unexpected non-nil slices: %#v, %#v", values, gotErrs)
+	}
+}
@@ -0,0 +1,246 @@
+package channels
+
+import (
+	"container/list"
+	"context"
+	"time"
+)
+
+// Coalesce takes an input channel and returns an output channel that combines
+// bursts of values into a single value. A burst is a sequence of values that
+// arrive less than d apart from each other: the first value of a burst is used
+// as the initial accumulated value, each following value is folded into it
+// with the combine function, and the accumulated value is emitted once d
+// elapses without new values.
+//
+// If the input channel is closed during a burst, the accumulated value is
+// emitted before the output channel is closed.
+//
+// The output channel is unbuffered.
+//
+// See WithClock for changing the clock used to wait for d.
+//
+// This is a non-blocking function: it launches a goroutine and returns the
+// channel for consumption. In order to stop the inner goroutine, one can close
+// the input channel or cancel the provided context.
+//
+// The output channel is always closed on cancellation, even if the input
+// channel is never closed. The value accumulated at the time of cancellation
+// is discarded.
+func Coalesce[T any](ctx context.Context, in <-chan T, combine func(acc, v T) T, d time.Duration, opts ...TimeOption) <-chan T {
+	options := newTimeOptions(opts)
+	out := make(chan T)
+	go func() {
+		defer close(out)
+		var (
+			acc     T
+			pending bool
+		)
+		timer := lazyTimer{clock: options.clock}
+		defer timer.Stop()
+		for {
+			select {
+			case v, ok := <-in:
+				if !ok {
+					if pending {
+						trySend(ctx, out, acc)
+					}
+					return
+				}
+				if pending {
+					acc = combine(acc, v)
+				} else {
+					acc = v
+					pending = true
+				}
+				timer.Reset(d)
+			case <-timer.C():
+				if !trySend(ctx, out, acc) {
+					return
+				}
+				var zero T
+				acc = zero
+				pending = false
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return out
+}
+
+// CoalesceByKey takes an input channel and returns an output channel that
+// debounces values per key, as returned by the key function: each value
+// replaces the pending value with the same key, and the pending value for a
+// key is emitted once d elapses without new values with that key. Values with
+// different keys don't affect each other, so a steady stream of values for a
+// key doesn't hold back the values for other keys.
+//
+// When the input channel is closed, all pending values are emitted, in the
+// order they were received, before the output channel is closed.
+//
+// The output channel is unbuffered.
+//
+// See WithClock for changing the clock used to wait for d.
+//
+// This is a non-blocking function: it launches a goroutine and returns the
+// channel for consumption. In order to stop the inner goroutine, one can close
+// the input channel or cancel the provided context.
+//
+// The output channel is always closed on cancellation, even if the input
+// channel is never closed. Pending values are discarded on cancellation.
+func CoalesceByKey[T any, K comparable](ctx context.Context, in <-chan T, key func(T) K, d time.Duration, opts ...TimeOption) <-chan T {
+	options := newTimeOptions(opts)
+	type entry struct {
+		key      K
+		value    T
+		deadline time.Time
+	}
+	out := make(chan T)
+	go func() {
+		defer close(out)
+		// pending holds one entry per key, ordered by deadline: as all keys
+		// share the same delay, an updated key moves to the back. A single
+		// timer is armed for the front entry.
+		pending := list.New()
+		elements := make(map[K]*list.Element)
+		timer := lazyTimer{clock: options.clock}
+		defer timer.Stop()
+		armed := false
+		for {
+			select {
+			case v, ok := <-in:
+				if !ok {
+					for el := pending.Front(); el != nil; el = el.Next() {
+						if !trySend(ctx, out, el.Value.(entry).value) {
+							return
+						}
+					}
+					return
+				}
+				k := key(v)
+				e := entry{key: k, value: v, deadline: options.clock.Now().Add(d)}
+				if el, ok := elements[k]; ok {
+					el.Value = e
+					pending.MoveToBack(el)
+				} else {
+					elements[k] = pending.PushBack(e)
+				}
+				if !armed {
+					timer.Reset(d)
+					armed = true
+				}
+			case <-timer.C():
+				armed = false
+				t := options.clock.Now()
+				for el := pending.Front(); el != nil; el = pending.Front() {
+					e := el.Value.(entry)
+					if e.deadline.After(t) {
+						timer.Reset(e.deadline.Sub(t))
+						armed = true
+						break
+					}
+					pending.Remove(el)
+					delete(elements, e.key)
+					if !trySend(ctx, out, e.value) {
+						return
+					}
+				}
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return out
+}
+
+// IdleWatch takes an input channel and returns an output channel that emits
+// the values from the input channel unchanged, calling onIdle whenever d
+// elapses without values being received. The idle period is reset by each
+// value received, and onIdle is called again for every further d without
+// values, so it can be called repeatedly during a long idle period. Time spent
+// waiting for the consumer of the output channel doesn't count as idle.
+//
+// The onIdle function is called from the goroutine of the operator, so it
+// should not block.
+//
+// The capacity of the output channel will be same as the capacity of the input
+// channel.
+//
+// See WithClock for changing the clock used to wait for d.
+//
+// This is a non-blocking function: it launches a goroutine and returns the
+// channel for consumption. In order to stop the inner goroutine, one can close
+// the input channel or cancel the provided context.
+//
+// The output channel is always closed on cancellation, even if the input
+// channel is never closed.
+func IdleWatch[T any](ctx context.Context, in <-chan T, d time.Duration, onIdle func(), opts ...TimeOption) <-chan T {
+	options := newTimeOptions(opts)
+	out := make(chan T, cap(in))
+	go func() {
+		defer close(out)
+		timer := options.clock.NewTimer(d)
+		defer timer.Stop()
+		for {
+			select {
+			case v, ok := <-in:
+				if !ok || !trySend(ctx, out, v) {
+					return
+				}
+				timer.Reset(d)
+			case <-timer.C():
+				onIdle()
+				timer.Reset(d)
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return out
+}
+
+// Heartbeat takes an input channel and returns an output channel that emits
+// the values from the input channel, interleaved with beat whenever d elapses
+// without anything being emitted, so that consumers that expect periodic
+// activity, such as keep-alive protocols, don't time out. The period is reset
+// after each value emitted, including beats.
+//
+// The output channel is unbuffered.
+//
+// See WithClock for changing the clock used to wait for d.
+//
+// This is a non-blocking function: it launches a goroutine and returns the
+// channel for consumption. In order to stop the inner goroutine, one can close
+// the input channel or cancel the provided context.
+//
+// The output channel is always closed on cancellation, even if the input
+// channel is never closed.
+func Heartbeat[T any](ctx context.Context, in <-chan T, d time.Duration, beat T, opts ...TimeOption) <-chan T {
+	options := newTimeOptions(opts)
+	out := make(chan T)
+	go func() {
+		defer close(out)
+		timer := options.clock.NewTimer(d)
+		defer timer.Stop()
+		for {
+			var v T
+			select {
+			case value, ok := <-in:
+				if !ok {
+					return
+				}
+				v = value
+			case <-timer.C():
+				v = beat
+			case <-ctx.Done():
+				return
+			}
+			if !trySend(ctx, out, v) {
+				return
+			}
+			timer.Reset(d)
+		}
+	}()
+	return out
+}
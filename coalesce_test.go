@@ -0,0 +1,295 @@
+package channels
+
+import (
+	"context"
+	"reflect"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestCoalesce(t *testing.T) {
+	t.Parallel()
+	in := make(chan int)
+	timers := make(chan chan time.Time, 10)
+	after := func(time.Duration) <-chan time.Time {
+		timer := make(chan time.Time, 1)
+		timers <- timer
+		return timer
+	}
+	sum := func(acc, v int) int { return acc + v }
+	out := Coalesce(context.TODO(), in, sum, time.Second, WithClock(testClock{after: after}))
+
+	in <- 1
+	first := <-timers
+	in <- 2
+	<-timers
+	in <- 3
+	last := <-timers
+
+	first <- time.Now()
+	last <- time.Now()
+	if got := <-out; got != 6 {
+		t.Errorf("wrong coalesced value\nwant 6\ngot  %d", got)
+	}
+
+	in <- 10
+	<-timers
+	close(in)
+	expected := []int{10}
+	if got := ToSlice(context.TODO(), out); !reflect.DeepEqual(got, expected) {
+		t.Errorf("wrong values returned\nwant %#v\ngot  %#v", expected, got)
+	}
+}
+
+func TestCoalesceWithRealTimer(t *testing.T) {
+	t.Parallel()
+	in := make(chan string, 3)
+	in <- "a"
+	in <- "b"
+	in <- "c"
+	out := Coalesce(context.TODO(), in, func(acc, v string) string { return acc + v }, 50*time.Millisecond)
+
+	if got := <-out; got != "abc" {
+		t.Errorf("wrong coalesced value\nwant %q\ngot  %q", "abc", got)
+	}
+	close(in)
+	if values := ToSlice(context.TODO(), out); values != nil {
+		t.Errorf("unexpected non-nil slice: %#v", values)
+	}
+}
+
+func TestCoalesceWithContextCancellation(t *testing.T) {
+	t.Parallel()
+	ch := startGenerator(t, 0, func(p int) (int, bool) {
+		return p + 1, true
+	}, nil)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	values := ToSlice(context.TODO(), Coalesce(ctx, ch, func(acc, v int) int { return acc + v }, time.Second))
+	if values != nil {
+		t.Errorf("unexpected non-nil slice: %#v", values)
+	}
+}
+
+func TestCoalesceByKey(t *testing.T) {
+	t.Parallel()
+	type update struct {
+		key   string
+		value int
+	}
+	in := make(chan update)
+	// each reading of the clock is provided by the test, so it happens
+	// exactly when the operator handles a value or a timer.
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	nows := make(chan time.Time)
+	now := func() time.Time {
+		return <-nows
+	}
+	timers := make(chan chan time.Time, 10)
+	after := func(time.Duration) <-chan time.Time {
+		timer := make(chan time.Time, 1)
+		timers <- timer
+		return timer
+	}
+	out := CoalesceByKey(context.TODO(), in, func(u update) string { return u.key }, time.Second, WithClock(testClock{now: now, after: after}))
+
+	in <- update{"a", 1}
+	nows <- start
+	timer := <-timers
+	in <- update{"b", 1}
+	nows <- start.Add(500 * time.Millisecond)
+	in <- update{"a", 2}
+	nows <- start.Add(800 * time.Millisecond)
+
+	// the update of a pushed its deadline past the deadline of b, so
+	// nothing is due when the timer fires.
+	timer <- time.Now()
+	nows <- start.Add(time.Second)
+	timer = <-timers
+	timer <- time.Now()
+	nows <- start.Add(1500 * time.Millisecond)
+	if got, expected := <-out, (update{"b", 1}); got != expected {
+		t.Errorf("wrong value returned\nwant %#v\ngot  %#v", expected, got)
+	}
+	timer = <-timers
+	timer <- time.Now()
+	nows <- start.Add(1800 * time.Millisecond)
+	if got, expected := <-out, (update{"a", 2}); got != expected {
+		t.Errorf("wrong value returned\nwant %#v\ngot  %#v", expected, got)
+	}
+
+	// a burst of updates shares a single timer.
+	in <- update{"b", 2}
+	nows <- start.Add(2 * time.Second)
+	<-timers
+	for i := 3; i < 100; i++ {
+		in <- update{"a", i}
+		nows <- start.Add(2 * time.Second)
+	}
+	close(in)
+	expected := []update{{"b", 2}, {"a", 99}}
+	if got := ToSlice(context.TODO(), out); !reflect.DeepEqual(got, expected) {
+		t.Errorf("wrong values returned\nwant %#v\ngot  %#v", expected, got)
+	}
+	if n := len(timers); n != 0 {
+		t.Errorf("unexpected timers created: %d", n)
+	}
+}
+
+func TestCoalesceByKeyWithContextCancellation(t *testing.T) {
+	t.Parallel()
+	ch := startGenerator(t, 0, func(p int) (int, bool) {
+		return p + 1, true
+	}, nil)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	values := ToSlice(context.TODO(), CoalesceByKey(ctx, ch, func(v int) int { return v % 2 }, time.Second))
+	if values != nil {
+		t.Errorf("unexpected non-nil slice: %#v", values)
+	}
+}
+
+func TestIdleWatch(t *testing.T) {
+	t.Parallel()
+	in := make(chan int)
+	timers := make(chan chan time.Time, 10)
+	after := func(time.Duration) <-chan time.Time {
+		timer := make(chan time.Time, 1)
+		timers <- timer
+		return timer
+	}
+	idle := make(chan struct{}, 10)
+	out := IdleWatch(context.TODO(), in, time.Second, func() { idle <- struct{}{} }, WithClock(testClock{after: after}))
+
+	// steady flow: each value resets the timer, so stale timers firing
+	// don't count.
+	first := <-timers
+	in <- 1
+	<-out
+	second := <-timers
+	first <- time.Now()
+	in <- 2
+	<-out
+	<-timers
+	second <- time.Now()
+	if len(idle) != 0 {
+		t.Errorf("unexpected idle notification during steady flow")
+	}
+
+	// a long gap fires onIdle repeatedly.
+	in <- 3
+	<-out
+	gap := <-timers
+	gap <- time.Now()
+	<-idle
+	gap = <-timers
+	gap <- time.Now()
+	<-idle
+	<-timers
+
+	close(in)
+	if values := ToSlice(context.TODO(), out); values != nil {
+		t.Errorf("unexpected values: %#v", values)
+	}
+}
+
+func TestIdleWatchWithRealTimer(t *testing.T) {
+	t.Parallel()
+	in := make(chan int)
+	var idle int32
+	out := IdleWatch(context.TODO(), in, 20*time.Millisecond, func() { atomic.AddInt32(&idle, 1) })
+	go func() {
+		defer close(in)
+		in <- 1
+		time.Sleep(70 * time.Millisecond)
+		in <- 2
+	}()
+
+	expected := []int{1, 2}
+	if values := ToSlice(context.TODO(), out); !reflect.DeepEqual(values, expected) {
+		t.Errorf("wrong values returned\nwant %#v\ngot  %#v", expected, values)
+	}
+	if n := atomic.LoadInt32(&idle); n < 2 {
+		t.Errorf("wrong number of idle notifications\nwant at least 2\ngot  %d", n)
+	}
+}
+
+func TestIdleWatchWithContextCancellation(t *testing.T) {
+	t.Parallel()
+	ch := startGenerator(t, 0, func(p int) (int, bool) {
+		return p + 1, true
+	}, func() { time.Sleep(time.Second) })
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	values := ToSlice(context.TODO(), IdleWatch(ctx, ch, 10*time.Millisecond, func() {}))
+	if values != nil {
+		t.Errorf("unexpected non-nil slice: %#v", values)
+	}
+}
+
+func TestHeartbeat(t *testing.T) {
+	t.Parallel()
+	in := make(chan string)
+	timers := make(chan chan time.Time, 10)
+	after := func(time.Duration) <-chan time.Time {
+		timer := make(chan time.Time, 1)
+		timers <- timer
+		return timer
+	}
+	out := Heartbeat(context.TODO(), in, time.Second, "beat", WithClock(testClock{after: after}))
+
+	// data flowing: no beats.
+	<-timers
+	in <- "a"
+	if got := <-out; got != "a" {
+		t.Errorf("wrong value returned\nwant %q\ngot  %q", "a", got)
+	}
+	<-timers
+	in <- "b"
+	if got := <-out; got != "b" {
+		t.Errorf("wrong value returned\nwant %q\ngot  %q", "b", got)
+	}
+
+	// idle gap: beats.
+	for i := 0; i < 2; i++ {
+		timer := <-timers
+		timer <- time.Now()
+		if got := <-out; got != "beat" {
+			t.Errorf("wrong value returned\nwant %q\ngot  %q", "beat", got)
+		}
+	}
+
+	<-timers
+	in <- "c"
+	if got := <-out; got != "c" {
+		t.Errorf("wrong value returned\nwant %q\ngot  %q", "c", got)
+	}
+	<-timers
+	close(in)
+	if values := ToSlice(context.TODO(), out); values != nil {
+		t.Errorf("unexpected values: %#v", values)
+	}
+}
+
+func TestHeartbeatWithContextCancellation(t *testing.T) {
+	t.Parallel()
+	ch := startGenerator(t, 0, func(p int) (int, bool) {
+		return p + 1, true
+	}, func() { time.Sleep(time.Second) })
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	values := ToSlice(context.TODO(), Heartbeat(ctx, ch, 30*time.Millisecond, -1))
+	if len(values) == 0 {
+		t.Fatal("unexpected empty slice")
+	}
+	for _, v := range values {
+		if v != -1 {
+			t.Errorf("unexpected value: %d", v)
+		}
+	}
+}
@@ -0,0 +1,21 @@
+package channels
+
+import "context"
+
+// Compose takes a list of stages and returns a function that chains them from
+// left to right: the output channel of each stage is the input channel of the
+// next one, and the output channel of the last stage is returned. Stages are
+// usually operators from this package with all their arguments, except for
+// the context and the input channel, already bound.
+//
+// The context provided to the returned function is passed to every stage, so
+// cancelling it stops all of them. Composing no stages returns a function that
+// returns the input channel unmodified.
+func Compose[T any](stages ...func(context.Context, <-chan T) <-chan T) func(context.Context, <-chan T) <-chan T {
+	return func(ctx context.Context, in <-chan T) <-chan T {
+		for _, stage := range stages {
+			in = stage(ctx, in)
+		}
+		return in
+	}
+}
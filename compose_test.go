@@ -0,0 +1,67 @@
+package channels
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestCompose(t *testing.T) {
+	t.Parallel()
+	ch := startGenerator(t, 0, func(p int) (int, bool) {
+		return p + 1, true
+	}, nil)
+
+	pipeline := Compose(
+		func(ctx context.Context, in <-chan int) <-chan int {
+			return Drop(ctx, in, 2)
+		},
+		func(ctx context.Context, in <-chan int) <-chan int {
+			return Filter(ctx, in, func(v int) bool { return v%2 == 0 })
+		},
+		func(ctx context.Context, in <-chan int) <-chan int {
+			return Take(ctx, in, 3)
+		},
+	)
+
+	values := ToSlice(context.TODO(), pipeline(context.TODO(), ch))
+	expectedSlice := []int{4, 6, 8}
+	if !reflect.DeepEqual(values, expectedSlice) {
+		t.Errorf("wrong values returned\nwant %#v\ngot  %#v", expectedSlice, values)
+	}
+}
+
+func TestComposeNoStages(t *testing.T) {
+	t.Parallel()
+	ch := make(chan int)
+	if got := Compose[int]()(context.TODO(), ch); got != (<-chan int)(ch) {
+		t.Errorf("unexpected channel returned by empty composition: %#v", got)
+	}
+}
+
+func TestComposeWithContextCancellation(t *testing.T) {
+	t.Parallel()
+	ch := startGenerator(t, 0, func(p int) (int, bool) {
+		return p + 1, true
+	}, func() { time.Sleep(10 * time.Millisecond) })
+
+	pipeline := Compose(
+		func(ctx context.Context, in <-chan int) <-chan int {
+			return Map(ctx, in, func(v int) int { return v * 2 })
+		},
+		func(ctx context.Context, in <-chan int) <-chan int {
+			return Filter(ctx, in, func(v int) bool { return v%4 == 0 })
+		},
+		func(ctx context.Context, in <-chan int) <-chan int {
+			return TakeWhile(ctx, in, func(int) bool { return true })
+		},
+	)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	values := ToSlice(context.TODO(), pipeline(ctx, ch))
+	if len(values) == 0 {
+		t.Fatal("unexpected empty slice")
+	}
+}
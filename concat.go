@@ -0,0 +1,84 @@
+package channels
+
+import "context"
+
+// Prepend takes two input channels and returns an output channel that will
+// emit all values from head, until head is closed, followed by all values from
+// in.
+//
+// The capacity of the output channel will be cap(in).
+//
+// This is a non-blocking function: it launches a goroutine and returns the
+// channel for consumption. In order to stop the inner goroutine, one can close
+// both input channels or cancel the provided context.
+//
+// The output channel is always closed on cancellation, even if the input
+// channels are never closed.
+func Prepend[T any](ctx context.Context, head, in <-chan T) <-chan T {
+	return concat(ctx, cap(in), head, in)
+}
+
+// Append takes two input channels and returns an output channel that will
+// emit all values from in, until in is closed, followed by all values from
+// tail.
+//
+// The capacity of the output channel will be cap(in).
+//
+// This is a non-blocking function: it launches a goroutine and returns the
+// channel for consumption. In order to stop the inner goroutine, one can close
+// both input channels or cancel the provided context.
+//
+// The output channel is always closed on cancellation, even if the input
+// channels are never closed.
+func Append[T any](ctx context.Context, in, tail <-chan T) <-chan T {
+	return concat(ctx, cap(in), in, tail)
+}
+
+// concat emits all values from each of the input channels, in order, moving to
+// the next channel once the previous one is closed.
+func concat[T any](ctx context.Context, capacity int, ins ...<-chan T) <-chan T {
+	out := make(chan T, capacity)
+	go func() {
+		defer close(out)
+		for _, in := range ins {
+			receiveLoop(ctx, in, func(v T) bool {
+				return trySend(ctx, out, v)
+			})
+			if ctx.Err() != nil {
+				return
+			}
+		}
+	}()
+	return out
+}
+
+// ConcatLazy takes a list of factory functions and returns an output channel
+// that emits all values from the channel returned by each of them, in order.
+// Each factory is only called, with the provided context, once the channel
+// returned by the previous one is closed, so sources aren't created before
+// they're needed.
+//
+// The output channel is unbuffered.
+//
+// This is a non-blocking function: it launches a goroutine and returns the
+// channel for consumption. In order to stop the inner goroutine, one can
+// cancel the provided context, in which case the remaining factories aren't
+// called.
+//
+// The output channel is always closed on cancellation or after the channels
+// returned by all factories are closed.
+func ConcatLazy[T any](ctx context.Context, factories ...func(context.Context) <-chan T) <-chan T {
+	out := make(chan T)
+	go func() {
+		defer close(out)
+		for _, factory := range factories {
+			if ctx.Err() != nil {
+				return
+			}
+			receiveLoop(ctx, factory(ctx), func(v T) bool {
+				return trySend(ctx, out, v)
+			})
+		}
+	}()
+	return out
+}
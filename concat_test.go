@@ -0,0 +1,149 @@
+package channels
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestPrepend(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name     string
+		head     []int
+		in       []int
+		expected []int
+	}{
+		{name: "both channels with values", head: []int{1, 2}, in: []int{3, 4, 5}, expected: []int{1, 2, 3, 4, 5}},
+		{name: "empty head", in: []int{3, 4, 5}, expected: []int{3, 4, 5}},
+		{name: "empty input", head: []int{1, 2}, expected: []int{1, 2}},
+		{name: "both channels empty"},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+			values := ToSlice(context.TODO(), Prepend(context.TODO(), fromSlice(t, test.head), fromSlice(t, test.in)))
+			if !reflect.DeepEqual(values, test.expected) {
+				t.Errorf("wrong values returned\nwant %#v\ngot  %#v", test.expected, values)
+			}
+		})
+	}
+}
+
+func TestAppend(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name     string
+		in       []int
+		tail     []int
+		expected []int
+	}{
+		{name: "both channels with values", in: []int{1, 2}, tail: []int{3, 4, 5}, expected: []int{1, 2, 3, 4, 5}},
+		{name: "empty input", tail: []int{3, 4, 5}, expected: []int{3, 4, 5}},
+		{name: "empty tail", in: []int{1, 2}, expected: []int{1, 2}},
+		{name: "both channels empty"},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+			values := ToSlice(context.TODO(), Append(context.TODO(), fromSlice(t, test.in), fromSlice(t, test.tail)))
+			if !reflect.DeepEqual(values, test.expected) {
+				t.Errorf("wrong values returned\nwant %#v\ngot  %#v", test.expected, values)
+			}
+		})
+	}
+}
+
+func TestAppendWithContextCancellation(t *testing.T) {
+	t.Parallel()
+	ch := startGenerator(t, 0, func(p int) (int, bool) {
+		return p + 1, true
+	}, func() { time.Sleep(10 * time.Millisecond) })
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	values := ToSlice(context.TODO(), Append(ctx, ch, fromSlice(t, []int{-1})))
+	if len(values) == 0 {
+		t.Fatal("unexpected empty slice")
+	}
+	for _, v := range values {
+		if v < 0 {
+			t.Errorf("unexpected value from tail after cancellation: %d", v)
+		}
+	}
+}
+
+func fromSlice[T any](t *testing.T, values []T) <-chan T {
+	t.Helper()
+	i := 0
+	var zero T
+	return startGenerator(t, zero, func(T) (T, bool) {
+		if i >= len(values) {
+			return zero, false
+		}
+		i++
+		return values[i-1], true
+	}, nil)
+}
+
+func TestConcatLazy(t *testing.T) {
+	t.Parallel()
+	first := make(chan int)
+	secondCalled := make(chan struct{})
+	factories := []func(context.Context) <-chan int{
+		func(context.Context) <-chan int { return first },
+		func(context.Context) <-chan int {
+			close(secondCalled)
+			return fromSlice(t, []int{3, 4})
+		},
+	}
+	out := ConcatLazy(context.TODO(), factories...)
+
+	for _, v := range []int{1, 2} {
+		first <- v
+		if got := <-out; got != v {
+			t.Errorf("wrong value returned\nwant %d\ngot  %d", v, got)
+		}
+	}
+	select {
+	case <-secondCalled:
+		t.Fatal("second factory called before the first source was closed")
+	case <-time.After(50 * time.Millisecond):
+	}
+	close(first)
+
+	values := ToSlice(context.TODO(), out)
+	expected := []int{3, 4}
+	if !reflect.DeepEqual(values, expected) {
+		t.Errorf("wrong values returned\nwant %#v\ngot  %#v", expected, values)
+	}
+}
+
+func TestConcatLazyWithContextCancellation(t *testing.T) {
+	t.Parallel()
+	ch := startGenerator(t, 0, func(p int) (int, bool) {
+		return p + 1, true
+	}, func() { time.Sleep(time.Second) })
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	var called bool
+	values := ToSlice(context.TODO(), ConcatLazy(ctx,
+		func(context.Context) <-chan int { return ch },
+		func(context.Context) <-chan int {
+			called = true
+			return nil
+		},
+	))
+	if values != nil {
+		t.Errorf("unexpected non-nil slice: %#v", values)
+	}
+	if called {
+		t.Error("unexpected call to the second factory after cancellation")
+	}
+}
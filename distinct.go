@@ -0,0 +1,147 @@
+package channels
+
+import (
+	"context"
+	"time"
+)
+
+// DistinctTTL takes an input channel and returns an output channel that
+// suppresses values whose key, as returned by the key function, was emitted
+// less than ttl ago. Once ttl elapses after a value is emitted, the next value
+// with the same key is emitted again.
+//
+// Expired keys are evicted as new values arrive, so memory usage is bounded
+// by the number of distinct keys emitted within ttl.
+//
+// The capacity of the output channel will be same as the capacity of the input
+// channel.
+//
+// See WithClock for changing the clock used to read the current time.
+//
+// This is a non-blocking function: it launches a goroutine and returns the
+// channel for consumption. In order to stop the inner goroutine, one can close
+// the input channel or cancel the provided context.
+//
+// The output channel is always closed on cancellation, even if the input
+// channel is never closed.
+func DistinctTTL[T any, K comparable](ctx context.Context, in <-chan T, key func(T) K, ttl time.Duration, opts ...TimeOption) <-chan T {
+	options := newTimeOptions(opts)
+	type entry struct {
+		key     K
+		expires time.Time
+	}
+	// The TTL is the same for all keys, so entries are queued in expiration
+	// order.
+	var queue []entry
+	seen := make(map[K]time.Time)
+	return Filter(ctx, in, func(v T) bool {
+		t := options.clock.Now()
+		for len(queue) > 0 && !t.Before(queue[0].expires) {
+			if expires := seen[queue[0].key]; expires.Equal(queue[0].expires) {
+				delete(seen, queue[0].key)
+			}
+			queue = queue[1:]
+		}
+
+		k := key(v)
+		if _, ok := seen[k]; ok {
+			return false
+		}
+		expires := t.Add(ttl)
+		seen[k] = expires
+		queue = append(queue, entry{key: k, expires: expires})
+		return true
+	})
+}
+
+// WindowedDistinct takes an input channel and returns an output channel that
+// suppresses values equal to any of the last size values emitted. If size is
+// lower than 1, no values are suppressed.
+//
+// Only the last size emitted values are kept in memory, so WindowedDistinct
+// can be used with infinite streams.
+//
+// The capacity of the output channel will be same as the capacity of the input
+// channel.
+//
+// This is a non-blocking function: it launches a goroutine and returns the
+// channel for consumption. In order to stop the inner goroutine, one can close
+// the input channel or cancel the provided context.
+//
+// The output channel is always closed on cancellation, even if the input
+// channel is never closed.
+func WindowedDistinct[T comparable](ctx context.Context, in <-chan T, size int) <-chan T {
+	if size < 1 {
+		return Filter(ctx, in, func(T) bool { return true })
+	}
+	window := make([]T, 0, size)
+	next := 0
+	counts := make(map[T]int, size)
+	return Filter(ctx, in, func(v T) bool {
+		if counts[v] > 0 {
+			return false
+		}
+		if len(window) < size {
+			window = append(window, v)
+		} else {
+			evicted := window[next]
+			if counts[evicted]--; counts[evicted] == 0 {
+				delete(counts, evicted)
+			}
+			window[next] = v
+			next = (next + 1) % size
+		}
+		counts[v]++
+		return true
+	})
+}
+
+// DistinctWithState takes an input channel and returns an output channel that
+// suppresses values that were already emitted or that are part of
+// initialSeen. It also returns a function that takes a snapshot of the values
+// seen so far, including initialSeen, in the order they were first seen.
+// Persisting the snapshot and providing it as initialSeen allows
+// deduplication to survive process restarts. Values are only recorded once
+// they're sent to the output channel, so a value discarded on cancellation
+// isn't part of the snapshot and is emitted again after a restart.
+//
+// The snapshot function is not safe for concurrent use with the inner
+// goroutine: it should only be called after the output channel is closed.
+//
+// The capacity of the output channel will be same as the capacity of the input
+// channel.
+//
+// This is a non-blocking function: it launches a goroutine and returns the
+// channel for consumption. In order to stop the inner goroutine, one can close
+// the input channel or cancel the provided context.
+//
+// The output channel is always closed on cancellation, even if the input
+// channel is never closed.
+func DistinctWithState[T comparable](ctx context.Context, in <-chan T, initialSeen []T) (<-chan T, func() []T) {
+	seen := make(map[T]struct{}, len(initialSeen))
+	var order []T
+	for _, v := range initialSeen {
+		if _, ok := seen[v]; !ok {
+			seen[v] = struct{}{}
+			order = append(order, v)
+		}
+	}
+	out := make(chan T, cap(in))
+	go func() {
+		defer close(out)
+		receiveLoop(ctx, in, func(v T) bool {
+			if _, ok := seen[v]; ok {
+				return true
+			}
+			if !trySend(ctx, out, v) {
+				return false
+			}
+			seen[v] = struct{}{}
+			order = append(order, v)
+			return true
+		})
+	}()
+	return out, func() []T {
+		return append([]T(nil), order...)
+	}
+}
@@ -0,0 +1,134 @@
+package channels
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDistinctTTL(t *testing.T) {
+	t.Parallel()
+	in := fromSlice(t, []string{"a", "b", "A", "a", "c", "b", "a", "B"})
+	now, _ := fakeClock(time.Second)
+
+	values := ToSlice(context.TODO(), DistinctTTL(context.TODO(), in, strings.ToLower, 4*time.Second, WithClock(testClock{now: now})))
+	// Times: a=0s b=1s A=2s a=3s c=4s b=5s a=6s B=7s. "a" expires at 4s,
+	// "b" at 5s and "c" at 8s; the second "b" and "a" expire at 9s and 10s.
+	expected := []string{"a", "b", "c", "b", "a"}
+	if !reflect.DeepEqual(values, expected) {
+		t.Errorf("wrong values returned\nwant %#v\ngot  %#v", expected, values)
+	}
+}
+
+func TestDistinctTTLWithContextCancellation(t *testing.T) {
+	t.Parallel()
+	ch := startGenerator(t, 0, func(p int) (int, bool) {
+		return p + 1, true
+	}, nil)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	values := ToSlice(context.TODO(), DistinctTTL(ctx, ch, func(v int) int { return v % 3 }, time.Hour))
+	expected := []int{1, 2, 3}
+	if !reflect.DeepEqual(values, expected) {
+		t.Errorf("wrong values returned\nwant %#v\ngot  %#v", expected, values)
+	}
+}
+
+func TestWindowedDistinct(t *testing.T) {
+	t.Parallel()
+	in := fromSlice(t, []int{1, 2, 1, 3, 2, 4, 1, 5, 5, 3, 4})
+	values := ToSlice(context.TODO(), WindowedDistinct(context.TODO(), in, 3))
+	// The window holds the last 3 emitted values: the second 1 and 2 are
+	// dropped while they're in the window, the third 1 passes once the
+	// window is [2 3 4], the second 5 is dropped, and the second 3 and 4
+	// pass once the window is [4 1 5] and [1 5 3].
+	expected := []int{1, 2, 3, 4, 1, 5, 3, 4}
+	if !reflect.DeepEqual(values, expected) {
+		t.Errorf("wrong values returned\nwant %#v\ngot  %#v", expected, values)
+	}
+}
+
+func TestWindowedDistinctZeroSize(t *testing.T) {
+	t.Parallel()
+	in := fromSlice(t, []int{1, 1, 2, 2})
+	values := ToSlice(context.TODO(), WindowedDistinct(context.TODO(), in, 0))
+	expected := []int{1, 1, 2, 2}
+	if !reflect.DeepEqual(values, expected) {
+		t.Errorf("wrong values returned\nwant %#v\ngot  %#v", expected, values)
+	}
+}
+
+func TestWindowedDistinctWithContextCancellation(t *testing.T) {
+	t.Parallel()
+	ch := startGenerator(t, 0, func(p int) (int, bool) {
+		return p + 1, true
+	}, nil)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	values := ToSlice(context.TODO(), WindowedDistinct(ctx, ch, 10))
+	if len(values) == 0 {
+		t.Fatal("unexpected empty slice")
+	}
+}
+
+func TestDistinctWithState(t *testing.T) {
+	t.Parallel()
+	ch := fromSlice(t, []string{"a", "b", "c", "a", "d", "b", "e"})
+
+	out, snapshot := DistinctWithState(context.TODO(), ch, []string{"b", "d"})
+	values := ToSlice(context.TODO(), out)
+	expected := []string{"a", "c", "e"}
+	if !reflect.DeepEqual(values, expected) {
+		t.Errorf("wrong values returned\nwant %#v\ngot  %#v", expected, values)
+	}
+
+	expectedSeen := []string{"b", "d", "a", "c", "e"}
+	if seen := snapshot(); !reflect.DeepEqual(seen, expectedSeen) {
+		t.Errorf("wrong snapshot returned\nwant %#v\ngot  %#v", expectedSeen, seen)
+	}
+}
+
+func TestDistinctWithStateWithContextCancellation(t *testing.T) {
+	t.Parallel()
+	ch := startGenerator(t, 0, func(p int) (int, bool) {
+		return p + 1, true
+	}, func() { time.Sleep(time.Second) })
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	out, snapshot := DistinctWithState(ctx, ch, []int{1})
+	if values := ToSlice(context.TODO(), out); values != nil {
+		t.Errorf("unexpected non-nil slice: %#v", values)
+	}
+	if seen, expected := snapshot(), []int{1}; !reflect.DeepEqual(seen, expected) {
+		t.Errorf("wrong snapshot returned\nwant %#v\ngot  %#v", expected, seen)
+	}
+}
+
+func TestDistinctWithStateDoesntRecordUnsentValues(t *testing.T) {
+	t.Parallel()
+	in := make(chan string)
+	ctx, cancel := context.WithCancel(context.Background())
+	out, snapshot := DistinctWithState(ctx, in, nil)
+
+	in <- "a"
+	if v := <-out; v != "a" {
+		t.Fatalf("wrong value returned\nwant %q\ngot  %q", "a", v)
+	}
+	// b is pending in the inner goroutine, as nobody reads the output
+	// channel, and it's discarded on cancellation.
+	in <- "b"
+	cancel()
+	time.Sleep(10 * time.Millisecond)
+	if values := ToSlice(context.TODO(), out); values != nil {
+		t.Errorf("unexpected non-nil slice: %#v", values)
+	}
+
+	if seen, expected := snapshot(), []string{"a"}; !reflect.DeepEqual(seen, expected) {
+		t.Errorf("wrong snapshot returned\nwant %#v\ngot  %#v", expected, seen)
+	}
+}
@@ -68,3 +68,28 @@ func DropUntil[T any](ctx context.Context, in <-chan T, f func(T) bool) <-chan T
 		return !f(v)
 	})
 }
+
+// DropUntilSignal takes an input channel and returns an output channel that
+// will skip values from the input channel until the start channel is closed or
+// receives a value, and then emit all the following values. The start channel
+// is checked as each value is received, so if it fires before the first
+// value, no values are skipped.
+//
+// The capacity of the output channel will be cap(inputChannel).
+//
+// This is a non-blocking function: it launches a goroutine and returns the
+// channel for consumption. In order to stop the inner goroutine, one can close
+// the input channel or cancel the provided context.
+//
+// The output channel is always closed on cancellation, even if the input
+// channel is never closed.
+func DropUntilSignal[T any](ctx context.Context, in <-chan T, start <-chan struct{}) <-chan T {
+	return DropWhile(ctx, in, func(T) bool {
+		select {
+		case <-start:
+			return false
+		default:
+			return true
+		}
+	})
+}
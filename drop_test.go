@@ -172,3 +172,57 @@ func TestDropUntilWithContextCancellation(t *testing.T) {
 		t.Errorf("wrong values returned\nwant %#v\ngot  %#v", expectedSlice, values)
 	}
 }
+
+func TestDropUntilSignal(t *testing.T) {
+	t.Parallel()
+
+	t.Run("drops values before the signal", func(t *testing.T) {
+		t.Parallel()
+		in := make(chan int)
+		start := make(chan struct{})
+		out := DropUntilSignal(context.TODO(), in, start)
+
+		in <- 1
+		in <- 2
+		// give the operator time to check the signal for the last value.
+		time.Sleep(10 * time.Millisecond)
+		close(start)
+		go func() {
+			defer close(in)
+			in <- 3
+			in <- 4
+		}()
+		values := ToSlice(context.TODO(), out)
+		expected := []int{3, 4}
+		if !reflect.DeepEqual(values, expected) {
+			t.Errorf("wrong values returned\nwant %#v\ngot  %#v", expected, values)
+		}
+	})
+
+	t.Run("signal fired before the first value", func(t *testing.T) {
+		t.Parallel()
+		start := make(chan struct{})
+		close(start)
+		values := ToSlice(context.TODO(), DropUntilSignal(context.TODO(), fromSlice(t, []int{1, 2, 3}), start))
+		expected := []int{1, 2, 3}
+		if !reflect.DeepEqual(values, expected) {
+			t.Errorf("wrong values returned\nwant %#v\ngot  %#v", expected, values)
+		}
+	})
+}
+
+func TestDropUntilSignalWithContextCancellation(t *testing.T) {
+	t.Parallel()
+	ch := startGenerator(t, 0, func(p int) (int, bool) {
+		return p + 1, true
+	}, func() { time.Sleep(time.Second) })
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	start := make(chan struct{})
+	close(start)
+	values := ToSlice(context.TODO(), DropUntilSignal(ctx, ch, start))
+	if values != nil {
+		t.Errorf("unexpected non-nil slice: %#v", values)
+	}
+}
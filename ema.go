@@ -0,0 +1,48 @@
+package channels
+
+import "context"
+
+// Numeric is a constraint that permits any integer or floating-point type.
+type Numeric interface {
+	~int | ~int8 | ~int16 | ~int32 | ~int64 |
+		~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 | ~uintptr |
+		~float32 | ~float64
+}
+
+// EMA takes an input channel of numbers and returns an output channel that
+// emits, for each value received, the exponential moving average of the
+// values received so far, computed as alpha*v + (1-alpha)*ema. The average is
+// seeded with the first value, so the first value emitted is the first value
+// received, converted to float64.
+//
+// alpha must be in the interval (0, 1]: higher values discount older values
+// faster, and an alpha of 1 emits the input values unchanged. EMA panics if
+// alpha is out of range.
+//
+// The capacity of the output channel will be same as the capacity of the
+// input channel.
+//
+// This is a non-blocking function: it launches a goroutine and returns the
+// channel for consumption. In order to stop the inner goroutine, one can close
+// the input channel or cancel the provided context.
+//
+// The output channel is always closed on cancellation, even if the input
+// channel is never closed.
+func EMA[T Numeric](ctx context.Context, in <-chan T, alpha float64) <-chan float64 {
+	if !(alpha > 0 && alpha <= 1) {
+		panic("channels: EMA alpha must be in the interval (0, 1]")
+	}
+	var (
+		ema    float64
+		seeded bool
+	)
+	return Map(ctx, in, func(v T) float64 {
+		if !seeded {
+			ema = float64(v)
+			seeded = true
+		} else {
+			ema = alpha*float64(v) + (1-alpha)*ema
+		}
+		return ema
+	})
+}
@@ -0,0 +1,57 @@
+package channels
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestEMA(t *testing.T) {
+	t.Parallel()
+	ch := fromSlice(t, []int{10, 20, 20, 0})
+
+	values := ToSlice(context.TODO(), EMA(context.TODO(), ch, 0.5))
+	expected := []float64{10, 15, 17.5, 8.75}
+	if !reflect.DeepEqual(values, expected) {
+		t.Errorf("wrong values returned\nwant %#v\ngot  %#v", expected, values)
+	}
+}
+
+func TestEMAInvalidAlpha(t *testing.T) {
+	t.Parallel()
+	var tests = []struct {
+		name  string
+		alpha float64
+	}{
+		{"zero", 0},
+		{"negative", -0.5},
+		{"greater than one", 1.5},
+	}
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+			defer func() {
+				if recover() == nil {
+					t.Errorf("expected panic for alpha %v", test.alpha)
+				}
+			}()
+			EMA(context.TODO(), make(chan int), test.alpha)
+		})
+	}
+}
+
+func TestEMAWithContextCancellation(t *testing.T) {
+	t.Parallel()
+	ch := startGenerator(t, 0, func(p int) (int, bool) {
+		return p + 1, true
+	}, func() { time.Sleep(time.Second) })
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	values := ToSlice(context.TODO(), EMA(ctx, ch, 0.5))
+	if values != nil {
+		t.Errorf("unexpected non-nil slice: %#v", values)
+	}
+}
@@ -0,0 +1,75 @@
+package channels
+
+import "context"
+
+// Gate takes an input channel and a control channel, and returns an output
+// channel that emits values from the input channel only while the gate is
+// open. The gate starts closed, and each value received from the control
+// channel opens (true) or closes (false) it. If the control channel is closed
+// while the gate is open, the gate stays open. If it's closed while the gate
+// is closed, the gate can never reopen, so the output channel is closed and a
+// held value, if any, is discarded.
+//
+// While the gate is closed, values are not received from the input channel,
+// so the gate applies backpressure upstream instead of buffering or dropping
+// values: no values are lost when the gate is closed and reopened. A value
+// that was already received when the gate closed is held and sent once the
+// gate reopens.
+//
+// The output channel is unbuffered.
+//
+// This is a non-blocking function: it launches a goroutine and returns the
+// channel for consumption. In order to stop the inner goroutine, one can close
+// the input channel, close the control channel while the gate is closed, or
+// cancel the provided context. As values are not received from the input
+// channel while the gate is closed, closing the input channel is only
+// observed once the gate opens.
+//
+// The output channel is always closed on cancellation, even if the input
+// channel is never closed.
+func Gate[T any](ctx context.Context, in <-chan T, open <-chan bool) <-chan T {
+	out := make(chan T)
+	go func() {
+		defer close(out)
+		var (
+			isOpen  bool
+			pending bool
+			next    T
+		)
+		for {
+			var (
+				recvCh <-chan T
+				sendCh chan<- T
+			)
+			if isOpen && pending {
+				sendCh = out
+			} else if isOpen {
+				recvCh = in
+			}
+			select {
+			case v, ok := <-recvCh:
+				if !ok {
+					return
+				}
+				next = v
+				pending = true
+			case sendCh <- next:
+				var zero T
+				next = zero
+				pending = false
+			case state, ok := <-open:
+				if !ok {
+					if !isOpen {
+						return
+					}
+					open = nil
+					continue
+				}
+				isOpen = state
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return out
+}
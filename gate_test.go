@@ -0,0 +1,87 @@
+package channels
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestGate(t *testing.T) {
+	t.Parallel()
+	ch := startGenerator(t, 0, func(p int) (int, bool) {
+		if p > 5 {
+			return p, false
+		}
+		return p + 1, true
+	}, nil)
+	open := make(chan bool)
+	out := Gate(context.TODO(), ch, open)
+
+	assertBlocked := func() {
+		t.Helper()
+		select {
+		case v := <-out:
+			t.Fatalf("unexpected value while the gate is closed: %d", v)
+		case <-time.After(50 * time.Millisecond):
+		}
+	}
+
+	assertBlocked()
+	open <- true
+	if v := <-out; v != 1 {
+		t.Errorf("wrong value returned\nwant 1\ngot  %d", v)
+	}
+	if v := <-out; v != 2 {
+		t.Errorf("wrong value returned\nwant 2\ngot  %d", v)
+	}
+
+	open <- false
+	assertBlocked()
+	open <- true
+	close(open)
+
+	expected := []int{3, 4, 5, 6}
+	if got := ToSlice(context.TODO(), out); !reflect.DeepEqual(got, expected) {
+		t.Errorf("wrong values returned\nwant %#v\ngot  %#v", expected, got)
+	}
+}
+
+func TestGateControlClosedWhileClosed(t *testing.T) {
+	t.Parallel()
+	in := make(chan int)
+	open := make(chan bool)
+	out := Gate(context.TODO(), in, open)
+
+	open <- true
+	in <- 1
+	open <- false
+	close(open)
+
+	done := make(chan []int)
+	go func() {
+		done <- ToSlice(context.TODO(), out)
+	}()
+	select {
+	case values := <-done:
+		if values != nil {
+			t.Errorf("unexpected non-nil slice: %#v", values)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("output channel wasn't closed after the control channel was closed with the gate closed")
+	}
+}
+
+func TestGateWithContextCancellation(t *testing.T) {
+	t.Parallel()
+	ch := startGenerator(t, 0, func(p int) (int, bool) {
+		return p + 1, true
+	}, nil)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	values := ToSlice(context.TODO(), Gate(ctx, ch, make(chan bool)))
+	if values != nil {
+		t.Errorf("unexpected non-nil slice: %#v", values)
+	}
+}
@@ -0,0 +1,144 @@
+package channels
+
+import (
+	"bufio"
+	"context"
+	"io"
+)
+
+// LinesOption is an option that can be provided to Lines.
+type LinesOption func(*linesOptions)
+
+type linesOptions struct {
+	maxLineSize int
+}
+
+// WithMaxLineSize sets the maximum size of a line read by Lines, in bytes.
+// Lines longer than the maximum size cause Lines to stop with an error.
+// Values lower than 1 are treated as the default value.
+//
+// The default value is bufio.MaxScanTokenSize.
+func WithMaxLineSize(n int) LinesOption {
+	return func(opts *linesOptions) {
+		if n < 1 {
+			n = bufio.MaxScanTokenSize
+		}
+		opts.maxLineSize = n
+	}
+}
+
+// Lines takes a reader and returns two channels: one with the lines read from
+// the reader, without the trailing end-of-line marker, and another one with
+// errors. Lines are split using bufio.ScanLines, and any error, including a
+// line that's longer than the maximum line size, is sent to the error channel
+// and stops reading.
+//
+// Both channels are unbuffered.
+//
+// This is a non-blocking function: it launches a goroutine and returns the
+// channels for consumption. In order to stop the inner goroutine, one can
+// cancel the provided context. Reading is not context-aware, so cancellation
+// only takes effect between lines, unless reads from the reader can also be
+// cancelled.
+//
+// The output and errors channels are always closed on cancellation or when
+// the reader reaches EOF.
+func Lines(ctx context.Context, r io.Reader, opts ...LinesOption) (<-chan string, <-chan error) {
+	options := linesOptions{maxLineSize: bufio.MaxScanTokenSize}
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	out := make(chan string)
+	errs := make(chan error)
+	go func() {
+		defer close(out)
+		defer close(errs)
+		scanner := bufio.NewScanner(r)
+		scanner.Buffer(make([]byte, 0, min(options.maxLineSize, 4096)), options.maxLineSize)
+		for ctx.Err() == nil && scanner.Scan() {
+			if !trySend(ctx, out, scanner.Text()) {
+				return
+			}
+		}
+		if err := scanner.Err(); err != nil {
+			trySend(ctx, errs, err)
+		}
+	}()
+	return out, errs
+}
+
+// WriteLines writes each string from the input channel to the provided
+// writer, followed by a newline.
+//
+// This is a blocking function that returns when the input channel is closed,
+// when a write fails or when the provided context is cancelled. It returns the
+// first write error, or ctx.Err() in case of cancellation. A write that
+// doesn't write the whole line without returning an error is reported as
+// io.ErrShortWrite.
+func WriteLines(ctx context.Context, w io.Writer, in <-chan string) error {
+	var err error
+	receiveLoop(ctx, in, func(line string) bool {
+		var n int
+		data := []byte(line + "\n")
+		n, err = w.Write(data)
+		if err == nil && n < len(data) {
+			err = io.ErrShortWrite
+		}
+		return err == nil
+	})
+	if err != nil {
+		return err
+	}
+	return ctx.Err()
+}
+
+// ChunkBytes takes an input channel of byte slices and returns an output
+// channel that emits the same bytes re-framed into slices of exactly
+// frameSize bytes, regardless of how the bytes are split in the input
+// channel. Bytes that don't fill a frame are carried over to the next input
+// slices, and once the input channel is closed, the remaining bytes are
+// emitted as a final, shorter frame. If frameSize is lower than 1, it's
+// treated as 1.
+//
+// The emitted frames never share memory with the input slices, so input
+// slices can be reused once they're sent.
+//
+// The capacity of the output channel will be same as the capacity of the input
+// channel.
+//
+// This is a non-blocking function: it launches a goroutine and returns the
+// channel for consumption. In order to stop the inner goroutine, one can close
+// the input channel or cancel the provided context.
+//
+// The output channel is always closed on cancellation, even if the input
+// channel is never closed. Carried over bytes are discarded on cancellation.
+func ChunkBytes(ctx context.Context, in <-chan []byte, frameSize int) <-chan []byte {
+	if frameSize < 1 {
+		frameSize = 1
+	}
+	out := make(chan []byte, cap(in))
+	go func() {
+		defer close(out)
+		frame := make([]byte, 0, frameSize)
+		ok := true
+		receiveLoop(ctx, in, func(data []byte) bool {
+			for len(data) > 0 {
+				n := min(frameSize-len(frame), len(data))
+				frame = append(frame, data[:n]...)
+				data = data[n:]
+				if len(frame) == frameSize {
+					if ok = trySend(ctx, out, frame); !ok {
+						return false
+					}
+					frame = make([]byte, 0, frameSize)
+				}
+			}
+			return true
+		})
+		if ok && len(frame) > 0 && ctx.Err() == nil {
+			trySend(ctx, out, frame)
+		}
+	}()
+	return out
+}
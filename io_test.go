@@ -0,0 +1,284 @@
+package channels
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLines(t *testing.T) {
+	t.Parallel()
+	input := "first line\nsecond line\r\n\nlast line without newline"
+	lines, errs := Lines(context.TODO(), strings.NewReader(input))
+	gotLines, gotErrs := collectValuesAndErrors(lines, errs)
+
+	expectedLines := []string{"first line", "second line", "", "last line without newline"}
+	if !reflect.DeepEqual(gotLines, expectedLines) {
+		t.Errorf("wrong lines returned\nwant %#v\ngot  %#v", expectedLines, gotLines)
+	}
+	if gotErrs != nil {
+		t.Errorf("unexpected errors: %#v", gotErrs)
+	}
+}
+
+func TestLinesLongLine(t *testing.T) {
+	t.Parallel()
+	longLine := strings.Repeat("a", bufio.MaxScanTokenSize+1)
+	input := "short line\n" + longLine + "\nanother short line\n"
+
+	t.Run("fails with the default max line size", func(t *testing.T) {
+		lines, errs := Lines(context.TODO(), strings.NewReader(input))
+		gotLines, gotErrs := collectValuesAndErrors(lines, errs)
+
+		expectedLines := []string{"short line"}
+		if !reflect.DeepEqual(gotLines, expectedLines) {
+			t.Errorf("wrong lines returned\nwant %#v\ngot  %#v", expectedLines, gotLines)
+		}
+		expectedErrs := []error{bufio.ErrTooLong}
+		if !reflect.DeepEqual(gotErrs, expectedErrs) {
+			t.Errorf("wrong errors returned\nwant %#v\ngot  %#v", expectedErrs, gotErrs)
+		}
+	})
+
+	t.Run("supports a custom max line size", func(t *testing.T) {
+		lines, errs := Lines(context.TODO(), strings.NewReader(input), WithMaxLineSize(2*bufio.MaxScanTokenSize))
+		gotLines, gotErrs := collectValuesAndErrors(lines, errs)
+
+		expectedLines := []string{"short line", longLine, "another short line"}
+		if !reflect.DeepEqual(gotLines, expectedLines) {
+			t.Errorf("wrong lines returned\nwant %#v\ngot  %#v", expectedLines, gotLines)
+		}
+		if gotErrs != nil {
+			t.Errorf("unexpected errors: %#v", gotErrs)
+		}
+	})
+
+	for _, n := range []int{0, -1} {
+		n := n
+		t.Run(fmt.Sprintf("treats %d as the default max line size", n), func(t *testing.T) {
+			lines, errs := Lines(context.TODO(), strings.NewReader(input), WithMaxLineSize(n))
+			gotLines, gotErrs := collectValuesAndErrors(lines, errs)
+
+			expectedLines := []string{"short line"}
+			if !reflect.DeepEqual(gotLines, expectedLines) {
+				t.Errorf("wrong lines returned\nwant %#v\ngot  %#v", expectedLines, gotLines)
+			}
+			expectedErrs := []error{bufio.ErrTooLong}
+			if !reflect.DeepEqual(gotErrs, expectedErrs) {
+				t.Errorf("wrong errors returned\nwant %#v\ngot  %#v", expectedErrs, gotErrs)
+			}
+		})
+	}
+}
+
+func TestLinesReaderError(t *testing.T) {
+	t.Parallel()
+	readErr := errors.New("something went wrong")
+	r := io.MultiReader(strings.NewReader("line 1\nline 2\n"), &failingReader{err: readErr})
+	lines, errs := Lines(context.TODO(), r)
+	gotLines, gotErrs := collectValuesAndErrors(lines, errs)
+
+	expectedLines := []string{"line 1", "line 2"}
+	if !reflect.DeepEqual(gotLines, expectedLines) {
+		t.Errorf("wrong lines returned\nwant %#v\ngot  %#v", expectedLines, gotLines)
+	}
+	expectedErrs := []error{readErr}
+	if !reflect.DeepEqual(gotErrs, expectedErrs) {
+		t.Errorf("wrong errors returned\nwant %#v\ngot  %#v", expectedErrs, gotErrs)
+	}
+}
+
+func TestLinesWithContextCancellation(t *testing.T) {
+	t.Parallel()
+	r, w := io.Pipe()
+	t.Cleanup(func() { r.Close() })
+	go func() {
+		for {
+			if _, err := io.WriteString(w, "line\n"); err != nil {
+				return
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	lines, errs := Lines(ctx, r)
+	gotLines, gotErrs := collectValuesAndErrors(lines, errs)
+	if len(gotLines) == 0 {
+		t.Fatal("unexpected empty slice")
+	}
+	if gotErrs != nil {
+		t.Errorf("unexpected errors: %#v", gotErrs)
+	}
+}
+
+type failingReader struct {
+	err error
+}
+
+func (r *failingReader) Read([]byte) (int, error) {
+	return 0, r.err
+}
+
+func TestWriteLines(t *testing.T) {
+	t.Parallel()
+	ch := startGenerator(t, 0, func(p int) (int, bool) {
+		if p > 2 {
+			return p, false
+		}
+		return p + 1, true
+	}, nil)
+
+	var buf strings.Builder
+	err := WriteLines(context.TODO(), &buf, Map(context.TODO(), ch, func(v int) string {
+		return strings.Repeat("x", v)
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := "x\nxx\nxxx\n"
+	if got := buf.String(); got != expected {
+		t.Errorf("wrong content written\nwant %q\ngot  %q", expected, got)
+	}
+}
+
+func TestWriteLinesWriteError(t *testing.T) {
+	t.Parallel()
+	ch := startGenerator(t, "line", func(v string) (string, bool) {
+		return v, true
+	}, nil)
+
+	writeErr := errors.New("disk is full")
+	w := &failingWriter{failAt: 3, err: writeErr}
+	err := WriteLines(context.TODO(), w, ch)
+	if err != writeErr {
+		t.Errorf("wrong error returned\nwant %#v\ngot  %#v", writeErr, err)
+	}
+	expected := "line\nline\n"
+	if got := w.buf.String(); got != expected {
+		t.Errorf("wrong content written\nwant %q\ngot  %q", expected, got)
+	}
+}
+
+func TestWriteLinesShortWrite(t *testing.T) {
+	t.Parallel()
+	ch := startGenerator(t, "line", func(v string) (string, bool) {
+		return v, true
+	}, nil)
+
+	w := &failingWriter{failAt: 2, short: true}
+	err := WriteLines(context.TODO(), w, ch)
+	if err != io.ErrShortWrite {
+		t.Errorf("wrong error returned\nwant %#v\ngot  %#v", io.ErrShortWrite, err)
+	}
+	expected := "line\nli"
+	if got := w.buf.String(); got != expected {
+		t.Errorf("wrong content written\nwant %q\ngot  %q", expected, got)
+	}
+}
+
+func TestWriteLinesWithContextCancellation(t *testing.T) {
+	t.Parallel()
+	ch := startGenerator(t, "line", func(v string) (string, bool) {
+		return v, true
+	}, func() { time.Sleep(time.Second) })
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	var buf strings.Builder
+	err := WriteLines(ctx, &buf, ch)
+	if err != context.DeadlineExceeded {
+		t.Errorf("wrong error returned\nwant %#v\ngot  %#v", context.DeadlineExceeded, err)
+	}
+}
+
+// failingWriter is a writer that fails on the write number failAt, either
+// with the given error or, when short is true, by writing only half of the
+// provided data.
+type failingWriter struct {
+	buf    strings.Builder
+	writes int
+	failAt int
+	short  bool
+	err    error
+}
+
+func (w *failingWriter) Write(p []byte) (int, error) {
+	w.writes++
+	if w.writes == w.failAt {
+		if w.short {
+			return w.buf.Write(p[:len(p)/2])
+		}
+		return 0, w.err
+	}
+	return w.buf.Write(p)
+}
+
+func TestChunkBytes(t *testing.T) {
+	t.Parallel()
+	var tests = []struct {
+		name      string
+		input     []string
+		frameSize int
+		expected  []string
+	}{
+		{
+			name:      "straddling inputs",
+			input:     []string{"abc", "defgh", "i", "jklmnopq"},
+			frameSize: 4,
+			expected:  []string{"abcd", "efgh", "ijkl", "mnop", "q"},
+		},
+		{
+			name:      "many small inputs",
+			input:     []string{"a", "b", "c", "d", "e", "f", "g"},
+			frameSize: 3,
+			expected:  []string{"abc", "def", "g"},
+		},
+		{
+			name:      "aligned inputs",
+			input:     []string{"ab", "cd"},
+			frameSize: 2,
+			expected:  []string{"ab", "cd"},
+		},
+		{
+			name:      "empty inputs",
+			input:     []string{"", "ab", ""},
+			frameSize: 2,
+			expected:  []string{"ab"},
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+			in := Map(context.TODO(), fromSlice(t, test.input), func(s string) []byte { return []byte(s) })
+			frames := Map(context.TODO(), ChunkBytes(context.TODO(), in, test.frameSize), func(b []byte) string { return string(b) })
+			values := ToSlice(context.TODO(), frames)
+			if !reflect.DeepEqual(values, test.expected) {
+				t.Errorf("wrong values returned\nwant %#v\ngot  %#v", test.expected, values)
+			}
+		})
+	}
+}
+
+func TestChunkBytesWithContextCancellation(t *testing.T) {
+	t.Parallel()
+	ch := startGenerator(t, nil, func([]byte) ([]byte, bool) {
+		return []byte("a"), true
+	}, func() { time.Sleep(time.Second) })
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	values := ToSlice(context.TODO(), ChunkBytes(ctx, ch, 2))
+	if values != nil {
+		t.Errorf("unexpected non-nil slice: %#v", values)
+	}
+}
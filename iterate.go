@@ -0,0 +1,103 @@
+package channels
+
+import "context"
+
+// Iterate returns a channel that emits initial, then step(initial), then
+// step(step(initial)) and so on, until it reaches a fixpoint: a value for
+// which step returns the value itself. The fixpoint is emitted only once.
+//
+// If step never converges, the output channel is never closed and the inner
+// goroutine runs until the provided context is cancelled, so callers should
+// either make sure the function converges or bound the iteration, by
+// cancelling the context or with an operator such as Take.
+//
+// The output channel is unbuffered.
+//
+// This is a non-blocking function: it launches a goroutine and returns the
+// channel for consumption. In order to stop the inner goroutine, one can
+// cancel the provided context.
+//
+// The output channel is always closed on cancellation or after the fixpoint
+// is emitted.
+func Iterate[T comparable](ctx context.Context, initial T, step func(T) T) <-chan T {
+	out := make(chan T)
+	go func() {
+		defer close(out)
+		v := initial
+		for trySend(ctx, out, v) {
+			next := step(v)
+			if next == v {
+				return
+			}
+			v = next
+		}
+	}()
+	return out
+}
+
+// FromFunc returns two channels: one with the values produced by repeated
+// calls to next, and another one for errors. Calls to next stop once it
+// returns false or an error. The error, if any, is sent to the error channel,
+// and the value returned along with it is discarded.
+//
+// The output channel is unbuffered. The capacity of the error channel will
+// always be 1, so the error is never lost if the consumer reads the output
+// channel until it's closed before reading the error channel.
+//
+// This is a non-blocking function: it launches a goroutine and returns the
+// channels for consumption. In order to stop the inner goroutine, one can
+// cancel the provided context: next is not called after cancellation.
+//
+// The output and errors channels are always closed on cancellation or after
+// next returns false or an error.
+func FromFunc[T any](ctx context.Context, next func() (T, bool, error)) (<-chan T, <-chan error) {
+	out := make(chan T)
+	errs := make(chan error, 1)
+	go func() {
+		defer close(out)
+		defer close(errs)
+		for ctx.Err() == nil {
+			v, ok, err := next()
+			if err != nil {
+				errs <- err
+				return
+			}
+			if !ok || !trySend(ctx, out, v) {
+				return
+			}
+		}
+	}()
+	return out, errs
+}
+
+// Pair groups a key and its value.
+type Pair[K, V any] struct {
+	Key   K
+	Value V
+}
+
+// FromMap returns a channel that emits each entry of the provided map as a
+// Pair, exactly once. As with any iteration over a map, the order of the
+// entries is unspecified and may differ between calls. The map must not be
+// modified until the output channel is closed.
+//
+// The output channel is unbuffered.
+//
+// This is a non-blocking function: it launches a goroutine and returns the
+// channel for consumption. In order to stop the inner goroutine, one can
+// cancel the provided context.
+//
+// The output channel is always closed on cancellation or after all entries
+// are sent.
+func FromMap[K comparable, V any](ctx context.Context, m map[K]V) <-chan Pair[K, V] {
+	out := make(chan Pair[K, V])
+	go func() {
+		defer close(out)
+		for k, v := range m {
+			if !trySend(ctx, out, Pair[K, V]{Key: k, Value: v}) {
+				return
+			}
+		}
+	}()
+	return out
+}
@@ -0,0 +1,131 @@
+package channels
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestIterate(t *testing.T) {
+	t.Parallel()
+	values := ToSlice(context.TODO(), Iterate(context.TODO(), 100, func(v int) int { return v / 2 }))
+	expectedSlice := []int{100, 50, 25, 12, 6, 3, 1, 0}
+	if !reflect.DeepEqual(values, expectedSlice) {
+		t.Errorf("wrong values returned\nwant %#v\ngot  %#v", expectedSlice, values)
+	}
+}
+
+func TestIterateInitialFixpoint(t *testing.T) {
+	t.Parallel()
+	values := ToSlice(context.TODO(), Iterate(context.TODO(), "done", func(v string) string { return v }))
+	expectedSlice := []string{"done"}
+	if !reflect.DeepEqual(values, expectedSlice) {
+		t.Errorf("wrong values returned\nwant %#v\ngot  %#v", expectedSlice, values)
+	}
+}
+
+func TestIterateWithContextCancellation(t *testing.T) {
+	t.Parallel()
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	values := ToSlice(context.TODO(), Iterate(ctx, 0, func(v int) int { return v + 1 }))
+	if len(values) == 0 {
+		t.Fatal("unexpected empty slice")
+	}
+}
+
+func TestFromFunc(t *testing.T) {
+	t.Parallel()
+	cursor := []string{"a", "b", "c"}
+	values, errs := FromFunc(context.TODO(), func() (string, bool, error) {
+		if len(cursor) == 0 {
+			return "", false, nil
+		}
+		v := cursor[0]
+		cursor = cursor[1:]
+		return v, true, nil
+	})
+
+	gotVals, gotErrs := collectValuesAndErrors(values, errs)
+	expectedVals := []string{"a", "b", "c"}
+	if !reflect.DeepEqual(gotVals, expectedVals) {
+		t.Errorf("wrong values returned\nwant %#v\ngot  %#v", expectedVals, gotVals)
+	}
+	if gotErrs != nil {
+		t.Errorf("unexpected errors: %#v", gotErrs)
+	}
+}
+
+func TestFromFuncError(t *testing.T) {
+	t.Parallel()
+	readErr := errors.New("cursor closed")
+	calls := 0
+	values, errs := FromFunc(context.TODO(), func() (int, bool, error) {
+		calls++
+		if calls == 2 {
+			return 0, false, readErr
+		}
+		return calls, true, nil
+	})
+
+	gotVals := ToSlice(context.TODO(), values)
+	expectedVals := []int{1}
+	if !reflect.DeepEqual(gotVals, expectedVals) {
+		t.Errorf("wrong values returned\nwant %#v\ngot  %#v", expectedVals, gotVals)
+	}
+	gotErrs := ToSlice(context.TODO(), errs)
+	expectedErrs := []error{readErr}
+	if !reflect.DeepEqual(gotErrs, expectedErrs) {
+		t.Errorf("wrong errors returned\nwant %#v\ngot  %#v", expectedErrs, gotErrs)
+	}
+	if calls != 2 {
+		t.Errorf("wrong number of calls\nwant 2\ngot  %d", calls)
+	}
+}
+
+func TestFromFuncWithContextCancellation(t *testing.T) {
+	t.Parallel()
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	values, errs := FromFunc(ctx, func() (int, bool, error) {
+		return 1, true, nil
+	})
+
+	gotVals, gotErrs := collectValuesAndErrors(values, errs)
+	if len(gotVals) == 0 {
+		t.Fatal("unexpected empty slice")
+	}
+	if gotErrs != nil {
+		t.Errorf("unexpected errors: %#v", gotErrs)
+	}
+}
+
+func TestFromMap(t *testing.T) {
+	t.Parallel()
+	m := map[string]int{"a": 1, "b": 2, "c": 3, "d": 4}
+
+	got := make(map[string]int)
+	for _, pair := range ToSlice(context.TODO(), FromMap(context.TODO(), m)) {
+		if _, ok := got[pair.Key]; ok {
+			t.Errorf("duplicate entry for key %q", pair.Key)
+		}
+		got[pair.Key] = pair.Value
+	}
+	if !reflect.DeepEqual(got, m) {
+		t.Errorf("wrong entries returned\nwant %#v\ngot  %#v", m, got)
+	}
+}
+
+func TestFromMapWithContextCancellation(t *testing.T) {
+	t.Parallel()
+	ctx, cancel := context.WithCancel(context.Background())
+	ch := FromMap(ctx, map[int]int{1: 1, 2: 2, 3: 3})
+	<-ch
+	cancel()
+	time.Sleep(10 * time.Millisecond)
+	if values := ToSlice(context.TODO(), ch); len(values) > 0 {
+		t.Errorf("unexpected values after cancellation: %#v", values)
+	}
+}
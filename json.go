@@ -0,0 +1,74 @@
+package channels
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+)
+
+// DecodeJSON takes a reader with a stream of JSON values, such as
+// newline-delimited JSON, and returns two channels: one with the decoded
+// values and another one with decoding errors.
+//
+// Values that are valid JSON but can't be decoded into T produce an error and
+// are skipped, and decoding continues with the next value. Any other error,
+// including malformed JSON and errors from the reader, is sent to the error
+// channel and stops decoding, as the decoder can't recover from it.
+//
+// Both channels are unbuffered.
+//
+// This is a non-blocking function: it launches a goroutine and returns the
+// channels for consumption. In order to stop the inner goroutine, one can
+// cancel the provided context. Decoding is not context-aware, so cancellation
+// only takes effect between values, unless reads from the reader can also be
+// cancelled.
+//
+// The output and errors channels are always closed on cancellation or when
+// the reader reaches EOF.
+func DecodeJSON[T any](ctx context.Context, r io.Reader) (<-chan T, <-chan error) {
+	out := make(chan T)
+	errs := make(chan error)
+	go func() {
+		defer close(out)
+		defer close(errs)
+		decoder := json.NewDecoder(r)
+		for ctx.Err() == nil {
+			var v T
+			err := decoder.Decode(&v)
+			if err == io.EOF {
+				return
+			}
+			if err != nil {
+				var typeErr *json.UnmarshalTypeError
+				if !trySend(ctx, errs, err) || !errors.As(err, &typeErr) {
+					return
+				}
+				continue
+			}
+			if !trySend(ctx, out, v) {
+				return
+			}
+		}
+	}()
+	return out, errs
+}
+
+// Encode writes each value from the input channel to the provided writer,
+// encoded as JSON and followed by a newline.
+//
+// This is a blocking function that returns when the input channel is closed,
+// when encoding or writing a value fails or when the provided context is
+// cancelled. It returns the first error, or ctx.Err() in case of cancellation.
+func Encode[T any](ctx context.Context, w io.Writer, in <-chan T) error {
+	var err error
+	encoder := json.NewEncoder(w)
+	receiveLoop(ctx, in, func(v T) bool {
+		err = encoder.Encode(v)
+		return err == nil
+	})
+	if err != nil {
+		return err
+	}
+	return ctx.Err()
+}
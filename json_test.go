@@ -0,0 +1,149 @@
+package channels
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+type jsonRecord struct {
+	Name  string `json:"name"`
+	Count int    `json:"count"`
+}
+
+func TestDecodeJSON(t *testing.T) {
+	t.Parallel()
+	input := `{"name":"a","count":1}
+{"name":"b","count":2}
+{"name":"c","count":3}
+`
+	values, errs := DecodeJSON[jsonRecord](context.TODO(), strings.NewReader(input))
+	gotVals, gotErrs := collectValuesAndErrors(values, errs)
+
+	expectedVals := []jsonRecord{{Name: "a", Count: 1}, {Name: "b", Count: 2}, {Name: "c", Count: 3}}
+	if !reflect.DeepEqual(gotVals, expectedVals) {
+		t.Errorf("wrong values returned\nwant %#v\ngot  %#v", expectedVals, gotVals)
+	}
+	if gotErrs != nil {
+		t.Errorf("unexpected errors: %#v", gotErrs)
+	}
+}
+
+func TestDecodeJSONMalformedRecord(t *testing.T) {
+	t.Parallel()
+	input := `{"name":"a","count":1}
+{"name":"b","count":2}
+{"name":"c",
+{"name":"d","count":4}
+`
+	values, errs := DecodeJSON[jsonRecord](context.TODO(), strings.NewReader(input))
+	gotVals, gotErrs := collectValuesAndErrors(values, errs)
+
+	expectedVals := []jsonRecord{{Name: "a", Count: 1}, {Name: "b", Count: 2}}
+	if !reflect.DeepEqual(gotVals, expectedVals) {
+		t.Errorf("wrong values returned\nwant %#v\ngot  %#v", expectedVals, gotVals)
+	}
+	if len(gotErrs) != 1 {
+		t.Errorf("wrong number of errors returned\nwant 1\ngot  %d (%#v)", len(gotErrs), gotErrs)
+	}
+}
+
+func TestDecodeJSONTypeMismatch(t *testing.T) {
+	t.Parallel()
+	input := `{"name":"a","count":1}
+{"name":"b","count":"two"}
+{"name":"c","count":3}
+`
+	values, errs := DecodeJSON[jsonRecord](context.TODO(), strings.NewReader(input))
+	gotVals, gotErrs := collectValuesAndErrors(values, errs)
+
+	expectedVals := []jsonRecord{{Name: "a", Count: 1}, {Name: "c", Count: 3}}
+	if !reflect.DeepEqual(gotVals, expectedVals) {
+		t.Errorf("wrong values returned\nwant %#v\ngot  %#v", expectedVals, gotVals)
+	}
+	if len(gotErrs) != 1 {
+		t.Errorf("wrong number of errors returned\nwant 1\ngot  %d (%#v)", len(gotErrs), gotErrs)
+	}
+}
+
+func TestDecodeJSONWithContextCancellation(t *testing.T) {
+	t.Parallel()
+	input := strings.Repeat(`{"name":"a","count":1}`+"\n", 10)
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	values, errs := DecodeJSON[jsonRecord](ctx, strings.NewReader(input))
+
+	<-ctx.Done()
+	time.Sleep(10 * time.Millisecond)
+	gotVals, gotErrs := collectValuesAndErrors(values, errs)
+	if len(gotVals) > 1 {
+		t.Errorf("unexpected values after cancellation: %#v", gotVals)
+	}
+	if gotErrs != nil {
+		t.Errorf("unexpected errors: %#v", gotErrs)
+	}
+}
+
+func collectValuesAndErrors[T any](values <-chan T, errs <-chan error) ([]T, []error) {
+	var gotVals []T
+	var gotErrs []error
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		gotVals = ToSlice(context.TODO(), values)
+	}()
+	go func() {
+		defer wg.Done()
+		gotErrs = ToSlice(context.TODO(), errs)
+	}()
+	wg.Wait()
+	return gotVals, gotErrs
+}
+
+func TestEncode(t *testing.T) {
+	t.Parallel()
+	ch := startGenerator(t, 0, func(p int) (int, bool) {
+		if p > 2 {
+			return p, false
+		}
+		return p + 1, true
+	}, nil)
+
+	var buf strings.Builder
+	err := Encode(context.TODO(), &buf, Map(context.TODO(), ch, func(v int) jsonRecord {
+		return jsonRecord{Name: "item", Count: v}
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := `{"name":"item","count":1}
+{"name":"item","count":2}
+{"name":"item","count":3}
+`
+	if got := buf.String(); got != expected {
+		t.Errorf("wrong content written\nwant %q\ngot  %q", expected, got)
+	}
+}
+
+func TestEncodeWriteError(t *testing.T) {
+	t.Parallel()
+	ch := startGenerator(t, 0, func(p int) (int, bool) {
+		return p + 1, true
+	}, nil)
+
+	writeErr := errors.New("disk is full")
+	w := &failingWriter{failAt: 3, err: writeErr}
+	err := Encode(context.TODO(), w, ch)
+	if err != writeErr {
+		t.Errorf("wrong error returned\nwant %#v\ngot  %#v", writeErr, err)
+	}
+	expected := "1\n2\n"
+	if got := w.buf.String(); got != expected {
+		t.Errorf("wrong content written\nwant %q\ngot  %q", expected, got)
+	}
+}
@@ -0,0 +1,42 @@
+package channels
+
+import (
+	"context"
+	"sync"
+)
+
+// Latest holds the most recent value received from a channel.
+//
+// A Latest is safe for concurrent use by multiple goroutines.
+type Latest[T any] struct {
+	mu    sync.RWMutex
+	value T
+	ok    bool
+}
+
+// KeepLatest returns a Latest that holds the most recent value from the
+// provided input channel.
+//
+// This is a non-blocking function: it launches a goroutine and returns the
+// Latest for reading. In order to stop the inner goroutine, one can close the
+// input channel or cancel the provided context. After that, the Latest keeps
+// holding the last value received.
+func KeepLatest[T any](ctx context.Context, in <-chan T) *Latest[T] {
+	l := &Latest[T]{}
+	go receiveLoop(ctx, in, func(v T) bool {
+		l.mu.Lock()
+		defer l.mu.Unlock()
+		l.value = v
+		l.ok = true
+		return true
+	})
+	return l
+}
+
+// Load returns the most recent value received from the input channel. The
+// boolean return value is false if no values were received yet.
+func (l *Latest[T]) Load() (T, bool) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	return l.value, l.ok
+}
@@ -0,0 +1,92 @@
+package channels
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestKeepLatest(t *testing.T) {
+	t.Parallel()
+	in := make(chan int)
+	l := KeepLatest(context.TODO(), in)
+
+	if v, ok := l.Load(); ok {
+		t.Errorf("unexpected value before any value is received: %d", v)
+	}
+
+	in <- 1
+	in <- 2
+	in <- 3
+	close(in)
+	waitLatest(t, l, 3)
+}
+
+func TestKeepLatestConcurrentLoad(t *testing.T) {
+	t.Parallel()
+	ch := startGenerator(t, 0, func(p int) (int, bool) {
+		if p > 999 {
+			return p, false
+		}
+		return p + 1, true
+	}, nil)
+	l := KeepLatest(context.TODO(), ch)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			prev := 0
+			for j := 0; j < 1000; j++ {
+				v, _ := l.Load()
+				if v < prev {
+					t.Errorf("value went back in time: %d after %d", v, prev)
+					return
+				}
+				prev = v
+			}
+		}()
+	}
+	wg.Wait()
+	waitLatest(t, l, 1000)
+}
+
+func TestKeepLatestWithContextCancellation(t *testing.T) {
+	t.Parallel()
+	ch := startGenerator(t, 0, func(p int) (int, bool) {
+		return p + 1, true
+	}, func() { time.Sleep(10 * time.Millisecond) })
+
+	ctx, cancel := context.WithCancel(context.Background())
+	l := KeepLatest(ctx, ch)
+	time.Sleep(50 * time.Millisecond)
+	cancel()
+	time.Sleep(10 * time.Millisecond)
+
+	v, ok := l.Load()
+	if !ok {
+		t.Fatal("unexpected empty Latest")
+	}
+	time.Sleep(50 * time.Millisecond)
+	if after, _ := l.Load(); after != v {
+		t.Errorf("value changed after cancellation: %d -> %d", v, after)
+	}
+}
+
+func waitLatest[T comparable](t *testing.T, l *Latest[T], expected T) {
+	t.Helper()
+	timeout := time.After(time.Second)
+	for {
+		if v, ok := l.Load(); ok && v == expected {
+			return
+		}
+		select {
+		case <-timeout:
+			v, ok := l.Load()
+			t.Fatalf("timed out waiting for latest value\nwant %v, true\ngot  %v, %t", expected, v, ok)
+		case <-time.After(time.Millisecond):
+		}
+	}
+}
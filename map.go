@@ -1,6 +1,9 @@
 package channels
 
-import "context"
+import (
+	"context"
+	"sync"
+)
 
 // Map takes an input channel and a function to transform values of the input
 // type to some other type, and returns a channel from the output type.
@@ -14,12 +17,75 @@ import "context"
 //
 // The output channel is always closed on cancellation, even if the input
 // channel is never closed.
-func Map[InputType, OutputType any](ctx context.Context, in <-chan InputType, f func(InputType) OutputType) <-chan OutputType {
+//
+// See WithOutputCap for changing the capacity of the output channel,
+// WithBufferGauge for observing its occupancy and WithRecover for recovering
+// from panics in the provided function.
+func Map[InputType, OutputType any](ctx context.Context, in <-chan InputType, f func(InputType) OutputType, opts ...MapOption) <-chan OutputType {
 	return FilterMap(ctx, in, func(v InputType) (OutputType, bool) {
 		return f(v), true
+	}, opts...)
+}
+
+// MapKeep is like Map, but emits each output value paired with the input
+// value it was produced from, as the Key and the Value of a Pair.
+//
+// The capacity of the output channel will be same as the capacity of the input
+// channel.
+//
+// This is a non-blocking function: it launches a goroutine and returns the
+// channel for consumption. In order to stop the inner goroutine, one can close
+// the input channel or cancel the provided context.
+//
+// The output channel is always closed on cancellation, even if the input
+// channel is never closed.
+func MapKeep[InputType, OutputType any](ctx context.Context, in <-chan InputType, f func(InputType) OutputType) <-chan Pair[InputType, OutputType] {
+	return Map(ctx, in, func(v InputType) Pair[InputType, OutputType] {
+		return Pair[InputType, OutputType]{Key: v, Value: f(v)}
+	})
+}
+
+// WithContextFunc sets a function that derives the context provided to the
+// mapping function of MapCtx for each element, from the context provided to
+// MapCtx and the index of the element in the input channel, starting at 0. It
+// can be used to attach per-element values or deadlines.
+//
+// By default, the mapping function receives the context provided to MapCtx.
+// Operators other than MapCtx ignore this option.
+func WithContextFunc(fn func(ctx context.Context, elementIndex int) context.Context) MapOption {
+	return mapOption(func(opts *operatorOptions) {
+		opts.contextFunc = fn
 	})
 }
 
+// MapCtx is like Map, but the function to transform values also takes a
+// context. See WithContextFunc for how to derive a different context for each
+// element.
+//
+// The capacity of the output channel will be same as the capacity of the input
+// channel.
+//
+// This is a non-blocking function: it launches a goroutine and returns the
+// channel for consumption. In order to stop the inner goroutine, one can close
+// the input channel or cancel the provided context.
+//
+// The output channel is always closed on cancellation, even if the input
+// channel is never closed.
+//
+// MapCtx accepts the same options as Map.
+func MapCtx[InputType, OutputType any](ctx context.Context, in <-chan InputType, f func(context.Context, InputType) OutputType, opts ...MapOption) <-chan OutputType {
+	contextFunc := newOperatorOptions(opts).contextFunc
+	if contextFunc == nil {
+		contextFunc = func(ctx context.Context, _ int) context.Context { return ctx }
+	}
+	i := 0
+	return Map(ctx, in, func(v InputType) OutputType {
+		elementCtx := contextFunc(ctx, i)
+		i++
+		return f(elementCtx, v)
+	}, opts...)
+}
+
 // FilterMap takes an input channel and a function that maps the input type to
 // an output type and a boolean, and returns a channel of OutputType that will
 // only include items for which the function return true.
@@ -33,18 +99,72 @@ func Map[InputType, OutputType any](ctx context.Context, in <-chan InputType, f
 //
 // The output channel is always closed on cancellation, even if the input
 // channel is never closed.
-func FilterMap[InputType, OutputType any](ctx context.Context, in <-chan InputType, f func(InputType) (OutputType, bool)) <-chan OutputType {
+//
+// See WithOutputCap for changing the capacity of the output channel,
+// WithBufferGauge for observing its occupancy and WithRecover for recovering
+// from panics in the provided function.
+func FilterMap[InputType, OutputType any](ctx context.Context, in <-chan InputType, f func(InputType) (OutputType, bool), opts ...MapOption) <-chan OutputType {
+	options := newOperatorOptions(opts)
+	out := make(chan OutputType, options.capacity(cap(in)))
+	go func() {
+		receiveLoop(ctx, in, func(v InputType) bool {
+			outValue, ok, cont := callRecovering(f, v, options)
+			if ok {
+				return sendObserved(ctx, out, outValue, options)
+			}
+			return cont
+		})
+		close(out)
+	}()
+	return out
+}
+
+// FilterMapIndex is like FilterMap, but the function also receives the index
+// of each value, which is the number of values read from the input channel
+// before it, regardless of how many of them were filtered out.
+//
+// See FilterMap for the details about the output channel and the supported
+// options.
+func FilterMapIndex[InputType, OutputType any](ctx context.Context, in <-chan InputType, f func(index int, v InputType) (OutputType, bool), opts ...MapOption) <-chan OutputType {
+	index := 0
+	return FilterMap(ctx, in, func(v InputType) (OutputType, bool) {
+		i := index
+		index++
+		return f(i, v)
+	}, opts...)
+}
+
+// FilterMapReject is like FilterMap, but instead of discarding the values for
+// which the function returns false, it sends them, unmodified, to a second
+// channel. Every value from the input channel ends up in exactly one of the
+// two output channels.
+//
+// Both output channels are fed by a single goroutine, so a consumer that
+// doesn't read one of them eventually blocks the other.
+//
+// The capacity of both output channels will be same as the capacity of the
+// input channel.
+//
+// This is a non-blocking function: it launches a goroutine and returns the
+// channels for consumption. In order to stop the inner goroutine, one can
+// close the input channel or cancel the provided context.
+//
+// The output channels are always closed on cancellation, even if the input
+// channel is never closed.
+func FilterMapReject[InputType, OutputType any](ctx context.Context, in <-chan InputType, f func(InputType) (OutputType, bool)) (<-chan OutputType, <-chan InputType) {
 	out := make(chan OutputType, cap(in))
+	rejected := make(chan InputType, cap(in))
 	go func() {
+		defer close(out)
+		defer close(rejected)
 		receiveLoop(ctx, in, func(v InputType) bool {
 			if outValue, ok := f(v); ok {
 				return trySend(ctx, out, outValue)
 			}
-			return true
+			return trySend(ctx, rejected, v)
 		})
-		close(out)
 	}()
-	return out
+	return out, rejected
 }
 
 // MapError takes an input channel and a function to transform values of the
@@ -58,8 +178,17 @@ func FilterMap[InputType, OutputType any](ctx context.Context, in <-chan InputTy
 //  - if the function returns an error, send it to the error channel and
 //    ignores the other value.
 //
+// Both channels are fed by a single goroutine, so the error channel must be
+// consumed along with the output channel: a consumer that only reads the
+// output channel blocks the goroutine on the first error, and unless the
+// provided context is eventually cancelled, the goroutine leaks. Consumers
+// should read both channels concurrently until they're closed, buffer errors
+// with WithErrorBuffer when the number of errors is bounded, or use
+// MapErrorLog when errors only need to be logged.
+//
 // The capacity of the output channel will be same as the capacity of the input
-// channel. The capacity of the error channel will always be 0.
+// channel. The capacity of the error channel will be 0, unless WithErrorBuffer
+// is provided.
 //
 // This is a non-blocking function: it launches a goroutine and returns the
 // channel for consumption. In order to stop the inner goroutine, one can close
@@ -67,15 +196,19 @@ func FilterMap[InputType, OutputType any](ctx context.Context, in <-chan InputTy
 //
 // The output and errors channels is always closed on cancellation, even if the
 // input channel is never closed.
-func MapError[InputType, OutputType any](ctx context.Context, in <-chan InputType, f func(InputType) (OutputType, error)) (<-chan OutputType, <-chan error) {
-	out := make(chan OutputType, cap(in))
-	errs := make(chan error)
+//
+// See WithOutputCap for changing the capacity of the output channel and
+// WithBufferGauge for observing its occupancy.
+func MapError[InputType, OutputType any](ctx context.Context, in <-chan InputType, f func(InputType) (OutputType, error), opts ...ErrorOption) (<-chan OutputType, <-chan error) {
+	options := newOperatorOptions(opts)
+	out := make(chan OutputType, options.capacity(cap(in)))
+	errs := make(chan error, options.errorBuffer)
 	go func() {
 		receiveLoop(ctx, in, func(v InputType) bool {
 			if outValue, err := f(v); err != nil {
 				return trySend(ctx, errs, err)
 			} else {
-				return trySend(ctx, out, outValue)
+				return sendObserved(ctx, out, outValue, options)
 			}
 		})
 		close(out)
@@ -83,3 +216,309 @@ func MapError[InputType, OutputType any](ctx context.Context, in <-chan InputTyp
 	}()
 	return out, errs
 }
+
+// MapErrorLog is like MapError, but instead of sending errors to a separate
+// channel, it calls the log function with each of them, so only the output
+// channel needs to be consumed. Values for which the function returns an
+// error are discarded.
+//
+// The log function is called from the goroutine of the operator, so it should
+// not block.
+//
+// The capacity of the output channel will be same as the capacity of the input
+// channel.
+//
+// This is a non-blocking function: it launches a goroutine and returns the
+// channel for consumption. In order to stop the inner goroutine, one can close
+// the input channel or cancel the provided context.
+//
+// The output channel is always closed on cancellation, even if the input
+// channel is never closed.
+func MapErrorLog[InputType, OutputType any](ctx context.Context, in <-chan InputType, f func(InputType) (OutputType, error), log func(error)) <-chan OutputType {
+	return FilterMap(ctx, in, func(v InputType) (OutputType, bool) {
+		outValue, err := f(v)
+		if err != nil {
+			log(err)
+			return outValue, false
+		}
+		return outValue, true
+	})
+}
+
+// FlatMapParallel takes an input channel and a function that expands each
+// value of the input type into a slice of values of the output type, and
+// returns a channel from the output type. The function is invoked concurrently
+// by the given number of workers, but the output channel preserves the order
+// of the input channel: every value produced for an input element is sent
+// before any value produced for the next input element.
+//
+// Slices that complete out of order are held in memory until all the slices
+// for preceding input elements have been sent. The number of slices held at
+// any given time is proportional to the number of workers, so a slow
+// invocation of the function stalls the other workers instead of causing
+// unbounded buffering. If workers is lower than 1, a single worker is used.
+//
+// The capacity of the output channel will be same as the capacity of the input
+// channel.
+//
+// This is a non-blocking function: it launches goroutines and returns the
+// channel for consumption. In order to stop the inner goroutines, one can
+// close the input channel or cancel the provided context. Invocations of the
+// function that are in progress during cancellation are allowed to complete,
+// but their results are discarded.
+//
+// The output channel is always closed on cancellation, even if the input
+// channel is never closed.
+func FlatMapParallel[InputType, OutputType any](ctx context.Context, in <-chan InputType, workers int, f func(InputType) []OutputType) <-chan OutputType {
+	if workers < 1 {
+		workers = 1
+	}
+
+	type job struct {
+		value  InputType
+		result chan<- []OutputType
+	}
+	jobs := make(chan job)
+	pending := make(chan chan []OutputType, workers)
+	out := make(chan OutputType, cap(in))
+
+	for i := 0; i < workers; i++ {
+		go func() {
+			for j := range jobs {
+				j.result <- f(j.value)
+			}
+		}()
+	}
+
+	go func() {
+		defer close(jobs)
+		defer close(pending)
+		receiveLoop(ctx, in, func(v InputType) bool {
+			result := make(chan []OutputType, 1)
+			if !trySend(ctx, pending, result) {
+				return false
+			}
+			return trySend(ctx, jobs, job{value: v, result: result})
+		})
+	}()
+
+	go func() {
+		defer close(out)
+		receiveLoop(ctx, pending, func(result chan []OutputType) bool {
+			select {
+			case values := <-result:
+				for _, v := range values {
+					if !trySend(ctx, out, v) {
+						return false
+					}
+				}
+				return true
+			case <-ctx.Done():
+				return false
+			}
+		})
+	}()
+	return out
+}
+
+// SwitchMap takes an input channel and a function that maps each value of the
+// input type to an inner channel of the output type, and returns a channel
+// with the values from the most recent inner channel. When a new value arrives
+// in the input channel, the context provided to the function for the previous
+// value is cancelled and the previous inner channel is abandoned: none of its
+// values are sent after that.
+//
+// The capacity of the output channel will be same as the capacity of the input
+// channel.
+//
+// This is a non-blocking function: it launches a goroutine and returns the
+// channel for consumption. In order to stop the inner goroutine, one can close
+// the input channel or cancel the provided context.
+//
+// The output channel is always closed on cancellation or after the input
+// channel and the last inner channel are closed.
+func SwitchMap[InputType, OutputType any](ctx context.Context, in <-chan InputType, f func(context.Context, InputType) <-chan OutputType) <-chan OutputType {
+	out := make(chan OutputType, cap(in))
+	go func() {
+		defer close(out)
+		// A single goroutine receives from the input channel and from the
+		// current inner channel and sends to the output channel, so a value
+		// from an inner channel that's pending when a new input value
+		// arrives is discarded rather than racing with the switch.
+		var (
+			inner       <-chan OutputType
+			cancelInner context.CancelFunc = func() {}
+			pending     OutputType
+			sendCh      chan<- OutputType
+		)
+		defer func() { cancelInner() }()
+		for in != nil || inner != nil || sendCh != nil {
+			receiveInner := inner
+			if sendCh != nil {
+				receiveInner = nil
+			}
+			select {
+			case v, ok := <-in:
+				if !ok {
+					in = nil
+					continue
+				}
+				cancelInner()
+				innerCtx, cancel := context.WithCancel(ctx)
+				cancelInner = cancel
+				inner = f(innerCtx, v)
+				sendCh = nil
+			case v, ok := <-receiveInner:
+				if !ok {
+					inner = nil
+					continue
+				}
+				pending = v
+				sendCh = out
+			case sendCh <- pending:
+				sendCh = nil
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return out
+}
+
+// ConcatMap takes an input channel and a function that maps each value of the
+// input type to an inner channel of the output type, and returns a channel
+// with the values of all inner channels, one inner channel at a time: the
+// inner channel for a value is consumed until it's closed before the function
+// is invoked for the next value, so values from different inner channels
+// never interleave.
+//
+// Values received from the input channel while an inner channel is being
+// consumed are buffered. The buffer is unbounded, so if values arrive faster
+// than inner channels are drained, memory usage grows without limit.
+//
+// The capacity of the output channel will be same as the capacity of the input
+// channel.
+//
+// This is a non-blocking function: it launches goroutines and returns the
+// channel for consumption. In order to stop the inner goroutines, one can close
+// the input channel or cancel the provided context.
+//
+// The output channel is always closed on cancellation or after the input
+// channel and all inner channels are closed.
+func ConcatMap[InputType, OutputType any](ctx context.Context, in <-chan InputType, f func(context.Context, InputType) <-chan OutputType) <-chan OutputType {
+	out := make(chan OutputType, cap(in))
+	go func() {
+		defer close(out)
+		receiveLoop(ctx, buffer(ctx, in), func(v InputType) bool {
+			receiveLoop(ctx, f(ctx, v), func(v OutputType) bool {
+				return trySend(ctx, out, v)
+			})
+			return ctx.Err() == nil
+		})
+	}()
+	return out
+}
+
+// MergeMap takes an input channel and a function that maps each value of the
+// input type to an inner channel of the output type, and returns a channel
+// with the values of all inner channels, merged as they arrive. At most
+// concurrency inner channels are consumed at the same time: the next value
+// from the input channel is only received once a slot is free. If concurrency
+// is lower than 1, inner channels are consumed one at a time.
+//
+// The capacity of the output channel will be same as the capacity of the input
+// channel.
+//
+// This is a non-blocking function: it launches goroutines and returns the
+// channel for consumption. In order to stop the inner goroutines, one can close
+// the input channel or cancel the provided context.
+//
+// The output channel is always closed on cancellation or after the input
+// channel and all inner channels are closed.
+func MergeMap[InputType, OutputType any](ctx context.Context, in <-chan InputType, concurrency int, f func(context.Context, InputType) <-chan OutputType) <-chan OutputType {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+	out := make(chan OutputType, cap(in))
+	go func() {
+		defer close(out)
+		var wg sync.WaitGroup
+		slots := make(chan struct{}, concurrency)
+		receiveLoop(ctx, in, func(v InputType) bool {
+			if !trySend(ctx, slots, struct{}{}) {
+				return false
+			}
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				defer func() { <-slots }()
+				receiveLoop(ctx, f(ctx, v), func(v OutputType) bool {
+					return trySend(ctx, out, v)
+				})
+			}()
+			return true
+		})
+		wg.Wait()
+	}()
+	return out
+}
+
+// MapErrorStop is like MapError, but stops at the first error: while MapError
+// sends every error to the error channel and keeps consuming the input
+// channel, MapErrorStop sends the first error returned by the function to the
+// error channel, stops consuming the input channel and closes both channels.
+// No values are sent to the output channel after the error.
+//
+// The capacity of the output channel will be same as the capacity of the input
+// channel. The capacity of the error channel will always be 1, so the error is
+// never lost if the consumer reads the output channel until it's closed before
+// reading the error channel.
+//
+// This is a non-blocking function: it launches a goroutine and returns the
+// channel for consumption. In order to stop the inner goroutine, one can close
+// the input channel or cancel the provided context.
+//
+// The output and errors channels are always closed on cancellation or after
+// the first error, even if the input channel is never closed.
+func MapErrorStop[InputType, OutputType any](ctx context.Context, in <-chan InputType, f func(InputType) (OutputType, error)) (<-chan OutputType, <-chan error) {
+	out := make(chan OutputType, cap(in))
+	errs := make(chan error, 1)
+	go func() {
+		defer close(out)
+		defer close(errs)
+		receiveLoop(ctx, in, func(v InputType) bool {
+			outValue, err := f(v)
+			if err != nil {
+				errs <- err
+				return false
+			}
+			return trySend(ctx, out, outValue)
+		})
+	}()
+	return out, errs
+}
+
+// Fork takes an input channel and a list of functions, and returns a channel
+// that emits, for each value of the input channel, a slice with the results of
+// applying each function to the value, in the order the functions are
+// provided. If no functions are provided, an empty slice is emitted for each
+// value.
+//
+// The capacity of the output channel will be same as the capacity of the input
+// channel.
+//
+// This is a non-blocking function: it launches a goroutine and returns the
+// channel for consumption. In order to stop the inner goroutine, one can close
+// the input channel or cancel the provided context.
+//
+// The output channel is always closed on cancellation, even if the input
+// channel is never closed.
+func Fork[InputType, OutputType any](ctx context.Context, in <-chan InputType, fs ...func(InputType) OutputType) <-chan []OutputType {
+	return Map(ctx, in, func(v InputType) []OutputType {
+		results := make([]OutputType, len(fs))
+		for i, f := range fs {
+			results[i] = f(v)
+		}
+		return results
+	})
+}
@@ -4,7 +4,9 @@ import (
 	"context"
 	"fmt"
 	"reflect"
+	"sort"
 	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 )
@@ -58,6 +60,95 @@ func TestMapWithContextCancellation(t *testing.T) {
 	}
 }
 
+func TestMapCtx(t *testing.T) {
+	t.Parallel()
+	ch := startGenerator(t, 0, func(p int) (int, bool) {
+		if p > 2 {
+			return p, false
+		}
+		return p + 1, true
+	}, nil)
+
+	type ctxKey struct{}
+	ctx := context.WithValue(context.Background(), ctxKey{}, "base")
+	values := MapCtx(ctx, ch, func(ctx context.Context, v int) string {
+		return fmt.Sprintf("%v-%d", ctx.Value(ctxKey{}), v)
+	})
+
+	expected := []string{"base-1", "base-2", "base-3"}
+	got := ToSlice(context.TODO(), values)
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("wrong values returned\nwant %#v\ngot  %#v", expected, got)
+	}
+}
+
+func TestMapCtxWithContextFunc(t *testing.T) {
+	t.Parallel()
+	ch := startGenerator(t, 0, func(p int) (int, bool) {
+		if p > 2 {
+			return p, false
+		}
+		return p + 1, true
+	}, nil)
+
+	type ctxKey struct{}
+	values := MapCtx(context.TODO(), ch, func(ctx context.Context, v int) string {
+		return fmt.Sprintf("%v-%d", ctx.Value(ctxKey{}), v)
+	}, WithContextFunc(func(ctx context.Context, i int) context.Context {
+		return context.WithValue(ctx, ctxKey{}, fmt.Sprintf("trace%d", i))
+	}))
+
+	expected := []string{"trace0-1", "trace1-2", "trace2-3"}
+	got := ToSlice(context.TODO(), values)
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("wrong values returned\nwant %#v\ngot  %#v", expected, got)
+	}
+}
+
+func TestMapCtxWithMapOptions(t *testing.T) {
+	t.Parallel()
+	type ctxKey struct{}
+	var recovered []any
+	out := MapCtx(context.TODO(), fromSlice(t, []int{1, 2, 3}), func(ctx context.Context, v int) string {
+		if v == 2 {
+			panic("bad input")
+		}
+		return fmt.Sprintf("%v-%d", ctx.Value(ctxKey{}), v)
+	}, WithContextFunc(func(ctx context.Context, i int) context.Context {
+		return context.WithValue(ctx, ctxKey{}, fmt.Sprintf("trace%d", i))
+	}), WithOutputCap(5), WithRecover(func(r any) bool {
+		recovered = append(recovered, r)
+		return true
+	}))
+	if c := cap(out); c != 5 {
+		t.Errorf("wrong capacity\nwant 5\ngot  %d", c)
+	}
+
+	expected := []string{"trace0-1", "trace2-3"}
+	if got := ToSlice(context.TODO(), out); !reflect.DeepEqual(got, expected) {
+		t.Errorf("wrong values returned\nwant %#v\ngot  %#v", expected, got)
+	}
+	if expectedRecovered := []any{"bad input"}; !reflect.DeepEqual(recovered, expectedRecovered) {
+		t.Errorf("wrong values recovered\nwant %#v\ngot  %#v", expectedRecovered, recovered)
+	}
+}
+
+func TestMapCtxWithContextCancellation(t *testing.T) {
+	t.Parallel()
+	ch := startGenerator(t, "foo", func(p string) (string, bool) {
+		return p, true
+	}, nil)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	lengths := MapCtx(ctx, ch, func(_ context.Context, v string) int { return len(v) })
+
+	got := ToSlice(context.TODO(), lengths)
+	if len(got) == 0 {
+		t.Fatal("unexpected empty slice")
+	}
+}
+
 func TestFilterMap(t *testing.T) {
 	t.Parallel()
 	ch := startGenerator(t, 0, func(p int) (int, bool) {
@@ -107,6 +198,115 @@ func TestFilterMapWithContextCancellation(t *testing.T) {
 	}
 }
 
+func TestFilterMapIndex(t *testing.T) {
+	t.Parallel()
+	ch := fromSlice(t, []string{"a", "bb", "ccc", "dddd", "eeeee", "ffffff", "g"})
+
+	var indexes []int
+	lengths := FilterMapIndex(context.TODO(), ch, func(i int, v string) (int, bool) {
+		indexes = append(indexes, i)
+		return len(v), i%2 == 0
+	})
+
+	expected := []int{1, 3, 5, 1}
+	got := ToSlice(context.TODO(), lengths)
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("wrong values returned\nwant %#v\ngot  %#v", expected, got)
+	}
+	expectedIndexes := []int{0, 1, 2, 3, 4, 5, 6}
+	if !reflect.DeepEqual(indexes, expectedIndexes) {
+		t.Errorf("wrong indexes\nwant %#v\ngot  %#v", expectedIndexes, indexes)
+	}
+}
+
+func TestFilterMapIndexWithContextCancellation(t *testing.T) {
+	t.Parallel()
+	ch := startGenerator(t, 0, func(p int) (int, bool) {
+		return p + 1, true
+	}, func() { time.Sleep(time.Second) })
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	values := ToSlice(context.TODO(), FilterMapIndex(ctx, ch, func(i, v int) (int, bool) {
+		return v, true
+	}))
+	if values != nil {
+		t.Errorf("unexpected non-nil slice: %#v", values)
+	}
+}
+
+func TestFilterMapReject(t *testing.T) {
+	t.Parallel()
+	ch := startGenerator(t, 0, func(p int) (int, bool) {
+		if p > 9 {
+			return p, false
+		}
+		return p + 1, true
+	}, nil)
+
+	labels, rejected := FilterMapReject(context.TODO(), ch, func(v int) (string, bool) {
+		return fmt.Sprintf("#%d", v), v%3 == 0
+	})
+	gotLabels, gotRejected := collectBoth(labels, rejected)
+
+	expectedLabels := []string{"#3", "#6", "#9"}
+	if !reflect.DeepEqual(gotLabels, expectedLabels) {
+		t.Errorf("wrong values returned\nwant %#v\ngot  %#v", expectedLabels, gotLabels)
+	}
+	expectedRejected := []int{1, 2, 4, 5, 7, 8, 10}
+	if !reflect.DeepEqual(gotRejected, expectedRejected) {
+		t.Errorf("wrong rejected values returned\nwant %#v\ngot  %#v", expectedRejected, gotRejected)
+	}
+}
+
+func TestFilterMapRejectWithContextCancellation(t *testing.T) {
+	t.Parallel()
+	ch := startGenerator(t, "foo", func(p string) (string, bool) {
+		return p, true
+	}, nil)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	lengths, rejected := FilterMapReject(ctx, ch, func(v string) (int, bool) { return len(v), false })
+	gotLengths, gotRejected := collectBoth(lengths, rejected)
+	if gotLengths != nil {
+		t.Errorf("unexpected non-nil slice: %#v", gotLengths)
+	}
+	if len(gotRejected) == 0 {
+		t.Fatal("unexpected empty slice")
+	}
+}
+
+func TestMapKeep(t *testing.T) {
+	t.Parallel()
+	out := MapKeep(context.TODO(), fromSlice(t, []int{1, 2, 3}), func(v int) string {
+		return fmt.Sprintf("value %d", v)
+	})
+	values := ToSlice(context.TODO(), out)
+	expected := []Pair[int, string]{
+		{Key: 1, Value: "value 1"},
+		{Key: 2, Value: "value 2"},
+		{Key: 3, Value: "value 3"},
+	}
+	if !reflect.DeepEqual(values, expected) {
+		t.Errorf("wrong values returned\nwant %#v\ngot  %#v", expected, values)
+	}
+}
+
+func TestMapKeepWithContextCancellation(t *testing.T) {
+	t.Parallel()
+	ch := startGenerator(t, 0, func(p int) (int, bool) {
+		return p + 1, true
+	}, func() { time.Sleep(time.Second) })
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	values := ToSlice(context.TODO(), MapKeep(ctx, ch, func(v int) int { return v }))
+	if values != nil {
+		t.Errorf("unexpected non-nil slice: %#v", values)
+	}
+}
+
 func TestMapError(t *testing.T) {
 	t.Parallel()
 	ch := startGenerator(t, 0, func(p int) (int, bool) {
@@ -152,3 +352,494 @@ func TestMapError(t *testing.T) {
 		t.Errorf("wrong errors returned\nwant %#v\ngot  %#v", expectedErrs, gotErrs)
 	}
 }
+
+func TestMapErrorUnreadErrors(t *testing.T) {
+	t.Parallel()
+	f := func(v int) (int, error) {
+		if v%2 == 0 {
+			return 0, fmt.Errorf("%d is even, don't like that", v)
+		}
+		return v * 2, nil
+	}
+
+	t.Run("blocks without an error buffer", func(t *testing.T) {
+		t.Parallel()
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		out, _ := MapError(ctx, fromSlice(t, []int{1, 2, 3}), f)
+		if got := <-out; got != 2 {
+			t.Errorf("wrong value returned\nwant 2\ngot  %d", got)
+		}
+		select {
+		case v, ok := <-out:
+			t.Errorf("unexpected receive while the error is unread: %d, %t", v, ok)
+		case <-time.After(50 * time.Millisecond):
+		}
+	})
+
+	t.Run("doesn't block with an error buffer", func(t *testing.T) {
+		t.Parallel()
+		out, errs := MapError(context.TODO(), fromSlice(t, []int{1, 2, 3, 4, 5}), f, WithErrorBuffer(2))
+		values := ToSlice(context.TODO(), out)
+		expected := []int{2, 6, 10}
+		if !reflect.DeepEqual(values, expected) {
+			t.Errorf("wrong values returned\nwant %#v\ngot  %#v", expected, values)
+		}
+		if gotErrs := ToSlice(context.TODO(), errs); len(gotErrs) != 2 {
+			t.Errorf("wrong number of errors returned\nwant 2\ngot  %d", len(gotErrs))
+		}
+	})
+}
+
+func TestMapErrorLog(t *testing.T) {
+	t.Parallel()
+	var logged []string
+	doubledOdds := MapErrorLog(context.TODO(), fromSlice(t, []int{1, 2, 3, 4, 5}), func(v int) (int, error) {
+		if v%2 == 0 {
+			return 0, fmt.Errorf("%d is even, don't like that", v)
+		}
+		return v * 2, nil
+	}, func(err error) {
+		logged = append(logged, err.Error())
+	})
+
+	values := ToSlice(context.TODO(), doubledOdds)
+	expectedVals := []int{2, 6, 10}
+	if !reflect.DeepEqual(values, expectedVals) {
+		t.Errorf("wrong values returned\nwant %#v\ngot  %#v", expectedVals, values)
+	}
+	expectedErrs := []string{
+		"2 is even, don't like that",
+		"4 is even, don't like that",
+	}
+	if !reflect.DeepEqual(logged, expectedErrs) {
+		t.Errorf("wrong errors logged\nwant %#v\ngot  %#v", expectedErrs, logged)
+	}
+}
+
+func TestMapErrorLogWithContextCancellation(t *testing.T) {
+	t.Parallel()
+	ch := startGenerator(t, 0, func(p int) (int, bool) {
+		return p + 1, true
+	}, func() { time.Sleep(time.Second) })
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	values := ToSlice(context.TODO(), MapErrorLog(ctx, ch, func(v int) (int, error) { return v, nil }, func(error) {}))
+	if values != nil {
+		t.Errorf("unexpected non-nil slice: %#v", values)
+	}
+}
+
+func TestFlatMapParallel(t *testing.T) {
+	t.Parallel()
+	ch := startGenerator(t, 0, func(p int) (int, bool) {
+		if p > 5 {
+			return p, false
+		}
+		return p + 1, true
+	}, nil)
+
+	expanded := FlatMapParallel(context.TODO(), ch, 4, func(v int) []int {
+		time.Sleep(time.Duration(7-v) * 10 * time.Millisecond)
+		return []int{v * 10, v*10 + 1}
+	})
+
+	expected := []int{10, 11, 20, 21, 30, 31, 40, 41, 50, 51, 60, 61}
+	got := ToSlice(context.TODO(), expanded)
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("wrong values returned\nwant %#v\ngot  %#v", expected, got)
+	}
+}
+
+func TestFlatMapParallelWithEmptySlices(t *testing.T) {
+	t.Parallel()
+	ch := startGenerator(t, 0, func(p int) (int, bool) {
+		if p > 5 {
+			return p, false
+		}
+		return p + 1, true
+	}, nil)
+
+	expanded := FlatMapParallel(context.TODO(), ch, 0, func(v int) []int {
+		if v%2 == 0 {
+			return nil
+		}
+		return []int{v, v}
+	})
+
+	expected := []int{1, 1, 3, 3, 5, 5}
+	got := ToSlice(context.TODO(), expanded)
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("wrong values returned\nwant %#v\ngot  %#v", expected, got)
+	}
+}
+
+func TestFlatMapParallelWithContextCancellation(t *testing.T) {
+	t.Parallel()
+	ch := startGenerator(t, "foo", func(p string) (string, bool) {
+		return p, true
+	}, nil)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	chars := FlatMapParallel(ctx, ch, 4, func(v string) []byte { return []byte(v) })
+
+	got := ToSlice(context.TODO(), chars)
+	if len(got) == 0 {
+		t.Fatal("unexpected empty slice")
+	}
+}
+
+func TestSwitchMap(t *testing.T) {
+	t.Parallel()
+	in := make(chan int, 3)
+	in <- 1
+	in <- 2
+	in <- 3
+	close(in)
+
+	var mu sync.Mutex
+	cancelled := map[int]bool{}
+	values := SwitchMap(context.TODO(), in, func(ctx context.Context, v int) <-chan int {
+		inner := make(chan int)
+		go func() {
+			defer close(inner)
+			select {
+			case <-time.After(50 * time.Millisecond):
+			case <-ctx.Done():
+				mu.Lock()
+				cancelled[v] = true
+				mu.Unlock()
+				return
+			}
+			for _, o := range []int{v * 10, v*10 + 1} {
+				if !Send(ctx, inner, o) {
+					return
+				}
+			}
+		}()
+		return inner
+	})
+
+	expected := []int{30, 31}
+	got := ToSlice(context.TODO(), values)
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("wrong values returned\nwant %#v\ngot  %#v", expected, got)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	expectedCancelled := map[int]bool{1: true, 2: true}
+	if !reflect.DeepEqual(cancelled, expectedCancelled) {
+		t.Errorf("wrong inner contexts cancelled\nwant %#v\ngot  %#v", expectedCancelled, cancelled)
+	}
+}
+
+func TestSwitchMapDiscardsPendingValueOnSwitch(t *testing.T) {
+	t.Parallel()
+	in := make(chan int)
+	values := SwitchMap(context.TODO(), in, func(_ context.Context, v int) <-chan int {
+		inner := make(chan int, 1)
+		inner <- v
+		close(inner)
+		return inner
+	})
+
+	// 1 is pending in the operator, as nobody reads the output channel,
+	// and it's discarded once 2 arrives.
+	in <- 1
+	time.Sleep(10 * time.Millisecond)
+	in <- 2
+	close(in)
+	expected := []int{2}
+	if got := ToSlice(context.TODO(), values); !reflect.DeepEqual(got, expected) {
+		t.Errorf("wrong values returned\nwant %#v\ngot  %#v", expected, got)
+	}
+}
+
+func TestSwitchMapWithContextCancellation(t *testing.T) {
+	t.Parallel()
+	ch := startGenerator(t, 0, func(p int) (int, bool) {
+		return p + 1, true
+	}, func() { time.Sleep(10 * time.Millisecond) })
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	values := SwitchMap(ctx, ch, func(ctx context.Context, v int) <-chan int {
+		return Take(ctx, startGenerator(t, v, func(p int) (int, bool) { return p, true }, nil), 1)
+	})
+
+	got := ToSlice(context.TODO(), values)
+	if len(got) == 0 {
+		t.Fatal("unexpected empty slice")
+	}
+}
+
+func TestConcatMap(t *testing.T) {
+	t.Parallel()
+	ch := startGenerator(t, 0, func(p int) (int, bool) {
+		if p > 2 {
+			return p, false
+		}
+		return p + 1, true
+	}, nil)
+
+	values := ConcatMap(context.TODO(), ch, func(ctx context.Context, v int) <-chan int {
+		inner := make(chan int)
+		go func() {
+			defer close(inner)
+			for i := 0; i < 3; i++ {
+				time.Sleep(time.Duration(4-v) * 5 * time.Millisecond)
+				if !Send(ctx, inner, v*10+i) {
+					return
+				}
+			}
+		}()
+		return inner
+	})
+
+	expected := []int{10, 11, 12, 20, 21, 22, 30, 31, 32}
+	got := ToSlice(context.TODO(), values)
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("wrong values returned\nwant %#v\ngot  %#v", expected, got)
+	}
+}
+
+func TestConcatMapBuffersInput(t *testing.T) {
+	t.Parallel()
+	in := make(chan int)
+	release := make(chan struct{})
+	values := ConcatMap(context.TODO(), in, func(ctx context.Context, v int) <-chan int {
+		inner := make(chan int, 1)
+		go func() {
+			defer close(inner)
+			<-release
+			inner <- v
+		}()
+		return inner
+	})
+
+	for i := 1; i <= 3; i++ {
+		select {
+		case in <- i:
+		case <-time.After(time.Second):
+			t.Fatalf("timed out sending value %d while an inner channel was open", i)
+		}
+	}
+	close(in)
+	close(release)
+
+	expected := []int{1, 2, 3}
+	got := ToSlice(context.TODO(), values)
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("wrong values returned\nwant %#v\ngot  %#v", expected, got)
+	}
+}
+
+func TestConcatMapWithContextCancellation(t *testing.T) {
+	t.Parallel()
+	ch := startGenerator(t, 0, func(p int) (int, bool) {
+		return p + 1, true
+	}, nil)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	values := ConcatMap(ctx, ch, func(ctx context.Context, v int) <-chan int {
+		return Take(ctx, startGenerator(t, v, func(p int) (int, bool) { return p, true }, nil), 2)
+	})
+
+	got := ToSlice(context.TODO(), values)
+	if len(got) == 0 {
+		t.Fatal("unexpected empty slice")
+	}
+}
+
+func TestMergeMap(t *testing.T) {
+	t.Parallel()
+	for _, concurrency := range []int{-1, 0, 1, 3} {
+		concurrency := concurrency
+		t.Run(fmt.Sprintf("concurrency=%d", concurrency), func(t *testing.T) {
+			t.Parallel()
+			ch := startGenerator(t, 0, func(p int) (int, bool) {
+				if p > 9 {
+					return p, false
+				}
+				return p + 1, true
+			}, nil)
+
+			var active, maxActive int32
+			values := MergeMap(context.TODO(), ch, concurrency, func(ctx context.Context, v int) <-chan int {
+				inner := make(chan int)
+				go func() {
+					defer close(inner)
+					n := atomic.AddInt32(&active, 1)
+					defer atomic.AddInt32(&active, -1)
+					for {
+						m := atomic.LoadInt32(&maxActive)
+						if n <= m || atomic.CompareAndSwapInt32(&maxActive, m, n) {
+							break
+						}
+					}
+					time.Sleep(10 * time.Millisecond)
+					Send(ctx, inner, v)
+					Send(ctx, inner, -v)
+				}()
+				return inner
+			})
+
+			got := ToSlice(context.TODO(), values)
+			sort.Ints(got)
+			expected := []int{-10, -9, -8, -7, -6, -5, -4, -3, -2, -1, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10}
+			if !reflect.DeepEqual(got, expected) {
+				t.Errorf("wrong values returned\nwant %#v\ngot  %#v", expected, got)
+			}
+
+			expectedMax := int32(concurrency)
+			if concurrency < 1 {
+				expectedMax = 1
+			}
+			if m := atomic.LoadInt32(&maxActive); m != expectedMax {
+				t.Errorf("wrong number of concurrent inner channels\nwant %d\ngot  %d", expectedMax, m)
+			}
+		})
+	}
+}
+
+func TestMergeMapWithContextCancellation(t *testing.T) {
+	t.Parallel()
+	ch := startGenerator(t, 0, func(p int) (int, bool) {
+		return p + 1, true
+	}, nil)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	values := MergeMap(ctx, ch, 2, func(ctx context.Context, v int) <-chan int {
+		return Take(ctx, startGenerator(t, v, func(p int) (int, bool) { return p, true }, nil), 2)
+	})
+
+	got := ToSlice(context.TODO(), values)
+	if len(got) == 0 {
+		t.Fatal("unexpected empty slice")
+	}
+}
+
+func TestMapErrorStop(t *testing.T) {
+	t.Parallel()
+	ch := startGenerator(t, 0, func(p int) (int, bool) {
+		return p + 1, true
+	}, nil)
+
+	doubled, errs := MapErrorStop(context.TODO(), ch, func(v int) (int, error) {
+		if v == 4 {
+			return 0, fmt.Errorf("%d is unlucky", v)
+		}
+		return v * 2, nil
+	})
+
+	gotVals := ToSlice(context.TODO(), doubled)
+	expectedVals := []int{2, 4, 6}
+	if !reflect.DeepEqual(gotVals, expectedVals) {
+		t.Errorf("wrong values returned\nwant %#v\ngot  %#v", expectedVals, gotVals)
+	}
+
+	gotErrs := ToSlice(context.TODO(), Map(context.TODO(), errs, func(err error) string { return err.Error() }))
+	expectedErrs := []string{"4 is unlucky"}
+	if !reflect.DeepEqual(gotErrs, expectedErrs) {
+		t.Errorf("wrong errors returned\nwant %#v\ngot  %#v", expectedErrs, gotErrs)
+	}
+
+	if next := <-ch; next != 5 {
+		t.Errorf("wrong next value in the input channel\nwant 5\ngot  %d", next)
+	}
+}
+
+func TestMapErrorStopWithClosedInputChannel(t *testing.T) {
+	t.Parallel()
+	ch := startGenerator(t, 0, func(p int) (int, bool) {
+		if p > 3 {
+			return p, false
+		}
+		return p + 1, true
+	}, nil)
+
+	doubled, errs := MapErrorStop(context.TODO(), ch, func(v int) (int, error) { return v * 2, nil })
+	gotVals, gotErrs := collectValuesAndErrors(doubled, errs)
+	expectedVals := []int{2, 4, 6, 8}
+	if !reflect.DeepEqual(gotVals, expectedVals) {
+		t.Errorf("wrong values returned\nwant %#v\ngot  %#v", expectedVals, gotVals)
+	}
+	if gotErrs != nil {
+		t.Errorf("unexpected errors: %#v", gotErrs)
+	}
+}
+
+func TestMapErrorStopWithContextCancellation(t *testing.T) {
+	t.Parallel()
+	ch := startGenerator(t, "foo", func(p string) (string, bool) {
+		return p, true
+	}, nil)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	lengths, errs := MapErrorStop(ctx, ch, func(v string) (int, error) { return len(v), nil })
+	gotVals, gotErrs := collectValuesAndErrors(lengths, errs)
+	if len(gotVals) == 0 {
+		t.Fatal("unexpected empty slice")
+	}
+	if gotErrs != nil {
+		t.Errorf("unexpected errors: %#v", gotErrs)
+	}
+}
+
+func TestFork(t *testing.T) {
+	t.Parallel()
+	ch := startGenerator(t, 0, func(p int) (int, bool) {
+		if p > 2 {
+			return p, false
+		}
+		return p + 1, true
+	}, nil)
+
+	results := Fork(context.TODO(), ch, func(v int) int { return v * v }, func(v int) int { return -v })
+
+	expected := [][]int{{1, -1}, {4, -2}, {9, -3}}
+	got := ToSlice(context.TODO(), results)
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("wrong values returned\nwant %#v\ngot  %#v", expected, got)
+	}
+}
+
+func TestForkNoFunctions(t *testing.T) {
+	t.Parallel()
+	ch := startGenerator(t, 0, func(p int) (int, bool) {
+		if p > 1 {
+			return p, false
+		}
+		return p + 1, true
+	}, nil)
+
+	results := Fork[int, string](context.TODO(), ch)
+
+	expected := [][]string{{}, {}}
+	got := ToSlice(context.TODO(), results)
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("wrong values returned\nwant %#v\ngot  %#v", expected, got)
+	}
+}
+
+func TestForkWithContextCancellation(t *testing.T) {
+	t.Parallel()
+	ch := startGenerator(t, "foo", func(p string) (string, bool) {
+		return p, true
+	}, nil)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	results := Fork(ctx, ch, func(v string) int { return len(v) })
+
+	got := ToSlice(context.TODO(), results)
+	if len(got) == 0 {
+		t.Fatal("unexpected empty slice")
+	}
+}
@@ -0,0 +1,78 @@
+package channels
+
+import "context"
+
+// NotificationKind identifies the kind of a Notification.
+type NotificationKind int
+
+const (
+	// NotificationNext indicates that the Notification carries a value from
+	// the stream.
+	NotificationNext NotificationKind = iota
+
+	// NotificationComplete indicates that the stream completed normally. A
+	// Notification of this kind doesn't carry a value.
+	NotificationComplete
+)
+
+// Notification represents an event in a stream: either a value or the
+// completion of the stream.
+type Notification[T any] struct {
+	Kind  NotificationKind
+	Value T
+}
+
+// Materialize takes an input channel and returns a channel of notifications
+// that wraps each value in a NotificationNext, followed by a single
+// NotificationComplete when the input channel is closed.
+//
+// The capacity of the output channel will be same as the capacity of the input
+// channel.
+//
+// This is a non-blocking function: it launches a goroutine and returns the
+// channel for consumption. In order to stop the inner goroutine, one can close
+// the input channel or cancel the provided context.
+//
+// The output channel is always closed on cancellation, even if the input
+// channel is never closed. No NotificationComplete is sent on cancellation.
+func Materialize[T any](ctx context.Context, in <-chan T) <-chan Notification[T] {
+	out := make(chan Notification[T], cap(in))
+	go func() {
+		defer close(out)
+		receiveLoop(ctx, in, func(v T) bool {
+			return trySend(ctx, out, Notification[T]{Kind: NotificationNext, Value: v})
+		})
+		if ctx.Err() == nil {
+			trySend(ctx, out, Notification[T]{Kind: NotificationComplete})
+		}
+	}()
+	return out
+}
+
+// Dematerialize is the inverse of Materialize: it takes a channel of
+// notifications and returns a channel with the values carried by
+// NotificationNext notifications. The output channel is closed when a
+// NotificationComplete is received.
+//
+// The capacity of the output channel will be same as the capacity of the input
+// channel.
+//
+// This is a non-blocking function: it launches a goroutine and returns the
+// channel for consumption. In order to stop the inner goroutine, one can close
+// the input channel or cancel the provided context.
+//
+// The output channel is always closed on cancellation or after receiving a
+// NotificationComplete, even if the input channel is never closed.
+func Dematerialize[T any](ctx context.Context, in <-chan Notification[T]) <-chan T {
+	out := make(chan T, cap(in))
+	go func() {
+		defer close(out)
+		receiveLoop(ctx, in, func(n Notification[T]) bool {
+			if n.Kind == NotificationComplete {
+				return false
+			}
+			return trySend(ctx, out, n.Value)
+		})
+	}()
+	return out
+}
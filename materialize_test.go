@@ -0,0 +1,92 @@
+package channels
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestMaterialize(t *testing.T) {
+	t.Parallel()
+	ch := startGenerator(t, 0, func(p int) (int, bool) {
+		if p > 2 {
+			return p, false
+		}
+		return p + 1, true
+	}, nil)
+
+	values := ToSlice(context.TODO(), Materialize(context.TODO(), ch))
+	expectedSlice := []Notification[int]{
+		{Kind: NotificationNext, Value: 1},
+		{Kind: NotificationNext, Value: 2},
+		{Kind: NotificationNext, Value: 3},
+		{Kind: NotificationComplete},
+	}
+	if !reflect.DeepEqual(values, expectedSlice) {
+		t.Errorf("wrong values returned\nwant %#v\ngot  %#v", expectedSlice, values)
+	}
+}
+
+func TestMaterializeWithContextCancellation(t *testing.T) {
+	t.Parallel()
+	ch := startGenerator(t, 0, func(p int) (int, bool) {
+		return p + 1, true
+	}, func() { time.Sleep(10 * time.Millisecond) })
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	values := ToSlice(context.TODO(), Materialize(ctx, ch))
+	if len(values) == 0 {
+		t.Fatal("unexpected empty slice")
+	}
+	for _, n := range values {
+		if n.Kind != NotificationNext {
+			t.Errorf("unexpected notification on cancellation: %#v", n)
+		}
+	}
+}
+
+func TestDematerialize(t *testing.T) {
+	t.Parallel()
+	ch := startGenerator(t, 0, func(p int) (int, bool) {
+		if p > 2 {
+			return p, false
+		}
+		return p + 1, true
+	}, nil)
+
+	values := ToSlice(context.TODO(), Dematerialize(context.TODO(), Materialize(context.TODO(), ch)))
+	expectedSlice := []int{1, 2, 3}
+	if !reflect.DeepEqual(values, expectedSlice) {
+		t.Errorf("wrong values returned\nwant %#v\ngot  %#v", expectedSlice, values)
+	}
+}
+
+func TestDematerializeStopsOnComplete(t *testing.T) {
+	t.Parallel()
+	ch := make(chan Notification[string], 4)
+	ch <- Notification[string]{Kind: NotificationNext, Value: "a"}
+	ch <- Notification[string]{Kind: NotificationComplete}
+	ch <- Notification[string]{Kind: NotificationNext, Value: "b"}
+
+	values := ToSlice(context.TODO(), Dematerialize(context.TODO(), ch))
+	expectedSlice := []string{"a"}
+	if !reflect.DeepEqual(values, expectedSlice) {
+		t.Errorf("wrong values returned\nwant %#v\ngot  %#v", expectedSlice, values)
+	}
+}
+
+func TestDematerializeWithContextCancellation(t *testing.T) {
+	t.Parallel()
+	ch := startGenerator(t, Notification[string]{}, func(n Notification[string]) (Notification[string], bool) {
+		return n, true
+	}, func() { time.Sleep(time.Second) })
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	values := ToSlice(context.TODO(), Dematerialize(ctx, ch))
+	if values != nil {
+		t.Errorf("unexpected non-nil slice: %#v", values)
+	}
+}
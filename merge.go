@@ -0,0 +1,522 @@
+package channels
+
+import (
+	"context"
+	"reflect"
+	"sync"
+)
+
+// Merge takes a list of input channels and returns an output channel that
+// emits the values from all input channels as they arrive. There are no
+// ordering guarantees between values from different input channels.
+//
+// The output channel is unbuffered. See MergeWithOptions for changing the
+// capacity of the output channel or observing its occupancy.
+//
+// This is a non-blocking function: it launches goroutines and returns the
+// channel for consumption. In order to stop the inner goroutines, one can close
+// all input channels or cancel the provided context.
+//
+// The output channel is always closed on cancellation or after all input
+// channels are closed.
+func Merge[T any](ctx context.Context, ins ...<-chan T) <-chan T {
+	return merge(ctx, ins, operatorOptions{})
+}
+
+// MergeWithOptions is like Merge, but takes options for configuring the
+// output channel: see WithOutputCap for changing its capacity and
+// WithBufferGauge for observing its occupancy.
+func MergeWithOptions[T any](ctx context.Context, ins []<-chan T, opts ...OperatorOption) <-chan T {
+	return merge(ctx, ins, newOperatorOptions(opts))
+}
+
+// merge emits the values from all input channels as they arrive. The returned
+// channel is closed once all goroutines reading from the input channels have
+// returned, either because all input channels were closed or because the
+// context was cancelled.
+func merge[T any](ctx context.Context, ins []<-chan T, options operatorOptions) <-chan T {
+	out := make(chan T, options.capacity(0))
+	options = options.serializeGauge()
+	var wg sync.WaitGroup
+	wg.Add(len(ins))
+	for _, in := range ins {
+		go func(in <-chan T) {
+			defer wg.Done()
+			receiveLoop(ctx, in, func(v T) bool {
+				return sendObserved(ctx, out, v, options)
+			})
+		}(in)
+	}
+	go func() {
+		wg.Wait()
+		close(out)
+	}()
+	return out
+}
+
+// FanOut takes an input channel and returns n output channels that share the
+// values from the input channel: each value is sent to exactly one of the
+// output channels, whichever is ready to receive it first. This is useful to
+// distribute work between n consumers. If n is lower than 1, a single output
+// channel is returned.
+//
+// The capacity of each output channel will be same as the capacity of the
+// input channel. See WithOutputCap for changing the capacity of the output
+// channels and WithBufferGauge for observing their occupancy.
+//
+// This is a non-blocking function: it launches goroutines and returns the
+// channels for consumption. In order to stop the inner goroutines, one can
+// close the input channel or cancel the provided context.
+//
+// The output channels are always closed on cancellation, even if the input
+// channel is never closed.
+func FanOut[T any](ctx context.Context, in <-chan T, n int, opts ...OperatorOption) []<-chan T {
+	if n < 1 {
+		n = 1
+	}
+	options := newOperatorOptions(opts).serializeGauge()
+	outs := make([]<-chan T, n)
+	for i := range outs {
+		out := make(chan T, options.capacity(cap(in)))
+		outs[i] = out
+		go func() {
+			defer close(out)
+			receiveLoop(ctx, in, func(v T) bool {
+				return sendObserved(ctx, out, v, options)
+			})
+		}()
+	}
+	return outs
+}
+
+// RoundRobinSplit takes an input channel and returns n output channels that
+// share the values from the input channel in round-robin order: the i-th value
+// received from the input channel (starting at 0) is sent to the output
+// channel i % n, so the first output channel receives the values 0, n, 2n and
+// so on. Unlike FanOut, routing depends only on the position of each value, so
+// it's deterministic. If n is lower than 1, a single output channel is
+// returned.
+//
+// All output channels are fed by a single goroutine, one value at a time, so a
+// consumer that doesn't read one of them eventually blocks the others.
+//
+// The capacity of each output channel will be same as the capacity of the
+// input channel. See WithOutputCap for changing the capacity of the output
+// channels and WithBufferGauge for observing their occupancy.
+//
+// This is a non-blocking function: it launches a goroutine and returns the
+// channels for consumption. In order to stop the inner goroutine, one can
+// close the input channel or cancel the provided context.
+//
+// The output channels are always closed together on cancellation, even if the
+// input channel is never closed.
+func RoundRobinSplit[T any](ctx context.Context, in <-chan T, n int, opts ...OperatorOption) []<-chan T {
+	if n < 1 {
+		n = 1
+	}
+	options := newOperatorOptions(opts)
+	chs := make([]chan T, n)
+	outs := make([]<-chan T, n)
+	for i := range chs {
+		chs[i] = make(chan T, options.capacity(cap(in)))
+		outs[i] = chs[i]
+	}
+	go func() {
+		defer func() {
+			for _, ch := range chs {
+				close(ch)
+			}
+		}()
+		i := 0
+		receiveLoop(ctx, in, func(v T) bool {
+			ch := chs[i%n]
+			i++
+			return sendObserved(ctx, ch, v, options)
+		})
+	}()
+	return outs
+}
+
+// Partition takes an input channel and a predicate function, and returns two
+// channels: matched emits the values for which the predicate returns true, and
+// unmatched emits all other values.
+//
+// Both output channels are fed by a single goroutine, so a consumer that
+// doesn't read one of them eventually blocks the other.
+//
+// The capacity of both output channels will be same as the capacity of the
+// input channel. See WithOutputCap for changing the capacity of the output
+// channels and WithBufferGauge for observing their occupancy.
+//
+// This is a non-blocking function: it launches a goroutine and returns the
+// channels for consumption. In order to stop the inner goroutine, one can
+// close the input channel or cancel the provided context.
+//
+// The output channels are always closed on cancellation, even if the input
+// channel is never closed.
+func Partition[T any](ctx context.Context, in <-chan T, predicate func(T) bool, opts ...OperatorOption) (matched <-chan T, unmatched <-chan T) {
+	options := newOperatorOptions(opts)
+	matchedCh := make(chan T, options.capacity(cap(in)))
+	unmatchedCh := make(chan T, options.capacity(cap(in)))
+	go func() {
+		defer close(matchedCh)
+		defer close(unmatchedCh)
+		receiveLoop(ctx, in, func(v T) bool {
+			if predicate(v) {
+				return sendObserved(ctx, matchedCh, v, options)
+			}
+			return sendObserved(ctx, unmatchedCh, v, options)
+		})
+	}()
+	return matchedCh, unmatchedCh
+}
+
+// FairMerge is like Merge, but it gives each input channel a turn, in
+// round-robin order, so that a fast input channel can't starve the others.
+// After a value is received from an input channel, the next value is taken
+// from the first of the following input channels that has a value ready,
+// wrapping around to the first input channel. If none of them is ready,
+// FairMerge waits for whichever input channel becomes ready first. Input
+// channels that are closed are skipped.
+//
+// In particular, when all input channels always have values ready, their
+// values are emitted alternately, in the order the input channels are
+// provided.
+//
+// The output channel is unbuffered.
+//
+// This is a non-blocking function: it launches a goroutine and returns the
+// channel for consumption. In order to stop the inner goroutine, one can close
+// all input channels or cancel the provided context.
+//
+// The output channel is always closed on cancellation or after all input
+// channels are closed.
+func FairMerge[T any](ctx context.Context, ins ...<-chan T) <-chan T {
+	out := make(chan T)
+	go func() {
+		defer close(out)
+		ins := append([]<-chan T(nil), ins...)
+		live := len(ins)
+		next := 0
+
+		// receive reads a value from any of the live input channels,
+		// checking them in turn starting at next and only blocking if none
+		// of them is ready. It returns false on cancellation or once all
+		// input channels are closed.
+		receive := func() (T, bool) {
+			for live > 0 {
+				for k := range ins {
+					i := (next + k) % len(ins)
+					if ins[i] == nil {
+						continue
+					}
+					select {
+					case v, ok := <-ins[i]:
+						if !ok {
+							ins[i] = nil
+							live--
+							continue
+						}
+						next = i + 1
+						return v, true
+					default:
+					}
+				}
+				if live == 0 {
+					break
+				}
+				cases := []reflect.SelectCase{{Dir: reflect.SelectRecv, Chan: reflect.ValueOf(ctx.Done())}}
+				indexes := []int{-1}
+				for i, in := range ins {
+					if in != nil {
+						cases = append(cases, reflect.SelectCase{Dir: reflect.SelectRecv, Chan: reflect.ValueOf(in)})
+						indexes = append(indexes, i)
+					}
+				}
+				chosen, v, ok := reflect.Select(cases)
+				if chosen == 0 {
+					break
+				}
+				i := indexes[chosen]
+				if !ok {
+					ins[i] = nil
+					live--
+					continue
+				}
+				next = i + 1
+				value, _ := v.Interface().(T)
+				return value, true
+			}
+			var zero T
+			return zero, false
+		}
+
+		for {
+			v, ok := receive()
+			if !ok || !trySend(ctx, out, v) {
+				return
+			}
+		}
+	}()
+	return out
+}
+
+// MergePriority is like Merge for two input channels, but it prefers the high
+// channel whenever it has a value ready, only emitting values from the low
+// channel while the high channel has none. This prevents a busy low-priority
+// channel from delaying urgent values, at the cost of possibly starving the
+// low channel while the high channel is busy.
+//
+// The output channel is unbuffered.
+//
+// This is a non-blocking function: it launches a goroutine and returns the
+// channel for consumption. In order to stop the inner goroutine, one can close
+// both input channels or cancel the provided context.
+//
+// The output channel is always closed on cancellation or after both input
+// channels are closed.
+func MergePriority[T any](ctx context.Context, high, low <-chan T) <-chan T {
+	out := make(chan T)
+	go func() {
+		defer close(out)
+		for high != nil || low != nil {
+			var (
+				v  T
+				ok bool
+			)
+			select {
+			case v, ok = <-high:
+				if !ok {
+					high = nil
+					continue
+				}
+			default:
+				select {
+				case v, ok = <-high:
+					if !ok {
+						high = nil
+						continue
+					}
+				case v, ok = <-low:
+					if !ok {
+						low = nil
+						continue
+					}
+				case <-ctx.Done():
+					return
+				}
+			}
+			if !trySend(ctx, out, v) {
+				return
+			}
+		}
+	}()
+	return out
+}
+
+// MergeDistinct is like Merge, but emits each distinct value only once,
+// regardless of which input channel it's received from or how many times.
+//
+// Every distinct value received is kept in memory, so memory usage grows
+// without bounds with the number of distinct values.
+//
+// The output channel is unbuffered.
+//
+// This is a non-blocking function: it launches goroutines and returns the
+// channel for consumption. In order to stop the inner goroutines, one can close
+// all input channels or cancel the provided context.
+//
+// The output channel is always closed on cancellation or after all input
+// channels are closed.
+func MergeDistinct[T comparable](ctx context.Context, ins ...<-chan T) <-chan T {
+	seen := make(map[T]struct{})
+	return Filter(ctx, merge(ctx, ins, operatorOptions{}), func(v T) bool {
+		if _, ok := seen[v]; ok {
+			return false
+		}
+		seen[v] = struct{}{}
+		return true
+	})
+}
+
+// Tagged is a value tagged with the index of the input channel it was
+// received from.
+type Tagged[T any] struct {
+	Index int
+	Value T
+}
+
+// MergeTagged is like Merge, but tags each value with the index of the input
+// channel it was received from, in the order the input channels are provided.
+//
+// The output channel is unbuffered.
+//
+// This is a non-blocking function: it launches goroutines and returns the
+// channel for consumption. In order to stop the inner goroutines, one can close
+// all input channels or cancel the provided context.
+//
+// The output channel is always closed on cancellation or after all input
+// channels are closed.
+func MergeTagged[T any](ctx context.Context, ins ...<-chan T) <-chan Tagged[T] {
+	tagged := make([]<-chan Tagged[T], len(ins))
+	for i, in := range ins {
+		i := i
+		tagged[i] = Map(ctx, in, func(v T) Tagged[T] {
+			return Tagged[T]{Index: i, Value: v}
+		})
+	}
+	return merge(ctx, tagged, operatorOptions{})
+}
+
+// Switch takes an input channel, a classify function and a list of keys, and
+// routes each value from the input channel to the output channel of the key
+// returned by classify. Values classified with a key that is not in the list
+// are sent to the other channel.
+//
+// All output channels are fed by a single goroutine, so a consumer that
+// doesn't read one of them eventually blocks all the others.
+//
+// The capacity of all output channels will be same as the capacity of the
+// input channel.
+//
+// This is a non-blocking function: it launches a goroutine and returns the
+// channels for consumption. In order to stop the inner goroutine, one can
+// close the input channel or cancel the provided context.
+//
+// All output channels are closed together, on cancellation or after the input
+// channel is closed.
+func Switch[T any, K comparable](ctx context.Context, in <-chan T, classify func(T) K, keys []K) (map[K]<-chan T, <-chan T) {
+	outs := make(map[K]chan T, len(keys))
+	result := make(map[K]<-chan T, len(keys))
+	for _, k := range keys {
+		if _, ok := outs[k]; !ok {
+			outs[k] = make(chan T, cap(in))
+			result[k] = outs[k]
+		}
+	}
+	other := make(chan T, cap(in))
+	go func() {
+		defer func() {
+			for _, out := range outs {
+				close(out)
+			}
+			close(other)
+		}()
+		receiveLoop(ctx, in, func(v T) bool {
+			if out, ok := outs[classify(v)]; ok {
+				return trySend(ctx, out, v)
+			}
+			return trySend(ctx, other, v)
+		})
+	}()
+	return result, other
+}
+
+// MergeErr calls each of the provided sources with a context derived from the
+// provided context and merges the values from the channels they return, like
+// Merge. On the first error received from any source, the derived context is
+// cancelled, so all sources can stop, and the error is sent to the error
+// channel, which receives at most one error. The error channel is buffered,
+// so the error is never lost if the consumer reads the output channel until
+// it's closed before reading the error channel.
+//
+// The output channel is unbuffered.
+//
+// This is a non-blocking function: it launches goroutines and returns the
+// channels for consumption. In order to stop the inner goroutines, one can
+// cancel the provided context.
+//
+// The output and errors channels are always closed on cancellation, after the
+// first error or after the channels of all sources are closed.
+func MergeErr[T any](ctx context.Context, sources ...func(context.Context) (<-chan T, <-chan error)) (<-chan T, <-chan error) {
+	ctx, cancel := context.WithCancel(ctx)
+	out := make(chan T)
+	errs := make(chan error, 1)
+	var (
+		wg       sync.WaitGroup
+		once     sync.Once
+		firstErr error
+	)
+	wg.Add(len(sources))
+	for _, source := range sources {
+		values, sourceErrs := source(ctx)
+		go func() {
+			defer wg.Done()
+			for values != nil || sourceErrs != nil {
+				select {
+				case v, ok := <-values:
+					if !ok {
+						values = nil
+						continue
+					}
+					if !trySend(ctx, out, v) {
+						return
+					}
+				case err, ok := <-sourceErrs:
+					if !ok {
+						sourceErrs = nil
+						continue
+					}
+					once.Do(func() {
+						firstErr = err
+						cancel()
+					})
+					return
+				case <-ctx.Done():
+					return
+				}
+			}
+		}()
+	}
+	go func() {
+		defer cancel()
+		wg.Wait()
+		close(out)
+		if firstErr != nil {
+			errs <- firstErr
+		}
+		close(errs)
+	}()
+	return out, errs
+}
+
+// TeeLossy takes an input channel and returns two output channels that both
+// emit the values from the input channel. Sends to the primary channel block
+// until the value is received, applying backpressure to the input channel,
+// while sends to the secondary channel never block: values that don't fit in
+// the buffer of the secondary channel are discarded. This makes the secondary
+// channel suitable for best-effort observers, such as monitoring taps, that
+// must not slow down the primary consumer. See WithDropCounter for observing
+// the number of discarded values.
+//
+// The capacity of both output channels will be same as the capacity of the
+// input channel. With an unbuffered input channel, values are only sent to
+// the secondary channel if its consumer is ready to receive them at the time.
+//
+// This is a non-blocking function: it launches a goroutine and returns the
+// channels for consumption. In order to stop the inner goroutine, one can
+// close the input channel or cancel the provided context.
+//
+// The output channels are always closed on cancellation, even if the input
+// channel is never closed.
+func TeeLossy[T any](ctx context.Context, in <-chan T, opts ...DropOption) (primary <-chan T, secondary <-chan T) {
+	options := newDropOptions(opts)
+	drops := dropReporter{dropCounter: options.dropCounter}
+	primaryCh := make(chan T, cap(in))
+	secondaryCh := make(chan T, cap(in))
+	go func() {
+		defer close(primaryCh)
+		defer close(secondaryCh)
+		defer drops.report()
+		receiveLoop(ctx, in, func(v T) bool {
+			select {
+			case secondaryCh <- v:
+				drops.report()
+			default:
+				drops.drop()
+			}
+			return trySend(ctx, primaryCh, v)
+		})
+	}()
+	return primaryCh, secondaryCh
+}
@@ -0,0 +1,593 @@
+package channels
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestMerge(t *testing.T) {
+	t.Parallel()
+	ins := []<-chan int{fromSlice(t, []int{1, 2, 3}), fromSlice(t, []int{4, 5}), fromSlice(t, []int(nil))}
+	values := ToSlice(context.TODO(), Merge(context.TODO(), ins...))
+	sort.Ints(values)
+	expected := []int{1, 2, 3, 4, 5}
+	if !reflect.DeepEqual(values, expected) {
+		t.Errorf("wrong values returned\nwant %#v\ngot  %#v", expected, values)
+	}
+}
+
+func TestMergeWithContextCancellation(t *testing.T) {
+	t.Parallel()
+	ch := startGenerator(t, "", func(v string) (string, bool) {
+		return v, true
+	}, func() { time.Sleep(time.Second) })
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	values := ToSlice(context.TODO(), Merge(ctx, ch))
+	if values != nil {
+		t.Errorf("unexpected non-nil slice: %#v", values)
+	}
+}
+
+func TestFanOut(t *testing.T) {
+	t.Parallel()
+	ch := startGenerator(t, 0, func(p int) (int, bool) {
+		if p > 9 {
+			return p, false
+		}
+		return p + 1, true
+	}, nil)
+
+	outs := FanOut(context.TODO(), ch, 3)
+	if len(outs) != 3 {
+		t.Fatalf("wrong number of output channels\nwant 3\ngot  %d", len(outs))
+	}
+
+	var (
+		mu     sync.Mutex
+		values []int
+		wg     sync.WaitGroup
+	)
+	for _, out := range outs {
+		wg.Add(1)
+		go func(out <-chan int) {
+			defer wg.Done()
+			got := ToSlice(context.TODO(), out)
+			mu.Lock()
+			defer mu.Unlock()
+			values = append(values, got...)
+		}(out)
+	}
+	wg.Wait()
+
+	sort.Ints(values)
+	expected := []int{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}
+	if !reflect.DeepEqual(values, expected) {
+		t.Errorf("wrong values returned\nwant %#v\ngot  %#v", expected, values)
+	}
+}
+
+func TestBufferGaugeWithConcurrentSenders(t *testing.T) {
+	t.Parallel()
+	// the gauge isn't guarded by a mutex: calls must be serialized by the
+	// operators, which the race detector checks.
+	var observed int
+	gauge := WithBufferGauge(func(int, int) {
+		observed++
+	})
+	newInputs := func() []<-chan int {
+		ins := make([]<-chan int, 4)
+		for i := range ins {
+			ins[i] = fromSlice(t, []int{1, 2, 3, 4, 5})
+		}
+		return ins
+	}
+
+	t.Run("MergeWithOptions", func(t *testing.T) {
+		observed = 0
+		if values := ToSlice(context.TODO(), MergeWithOptions(context.TODO(), newInputs(), gauge)); len(values) != 20 {
+			t.Fatalf("wrong number of values returned\nwant 20\ngot  %d", len(values))
+		}
+		if observed != 20 {
+			t.Errorf("wrong number of observations\nwant 20\ngot  %d", observed)
+		}
+	})
+
+	t.Run("FanOut", func(t *testing.T) {
+		observed = 0
+		outs := FanOut(context.TODO(), Merge(context.TODO(), newInputs()...), 4, gauge)
+		values := ToSlice(context.TODO(), Merge(context.TODO(), outs...))
+		if len(values) != 20 {
+			t.Fatalf("wrong number of values returned\nwant 20\ngot  %d", len(values))
+		}
+		if observed != 20 {
+			t.Errorf("wrong number of observations\nwant 20\ngot  %d", observed)
+		}
+	})
+}
+
+func TestFanOutWithContextCancellation(t *testing.T) {
+	t.Parallel()
+	ch := startGenerator(t, "", func(v string) (string, bool) {
+		return v, true
+	}, func() { time.Sleep(time.Second) })
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	for _, out := range FanOut(ctx, ch, 2) {
+		if values := ToSlice(context.TODO(), out); values != nil {
+			t.Errorf("unexpected non-nil slice: %#v", values)
+		}
+	}
+}
+
+func TestRoundRobinSplit(t *testing.T) {
+	t.Parallel()
+	ch := fromSlice(t, []int{0, 1, 2, 3, 4, 5, 6, 7, 8})
+
+	outs := RoundRobinSplit(context.TODO(), ch, 3)
+	if len(outs) != 3 {
+		t.Fatalf("wrong number of output channels\nwant 3\ngot  %d", len(outs))
+	}
+
+	values := make([][]int, len(outs))
+	var wg sync.WaitGroup
+	for i, out := range outs {
+		wg.Add(1)
+		go func(i int, out <-chan int) {
+			defer wg.Done()
+			values[i] = ToSlice(context.TODO(), out)
+		}(i, out)
+	}
+	wg.Wait()
+
+	expected := [][]int{{0, 3, 6}, {1, 4, 7}, {2, 5, 8}}
+	if !reflect.DeepEqual(values, expected) {
+		t.Errorf("wrong values returned\nwant %#v\ngot  %#v", expected, values)
+	}
+}
+
+func TestRoundRobinSplitWithContextCancellation(t *testing.T) {
+	t.Parallel()
+	ch := startGenerator(t, "", func(v string) (string, bool) {
+		return v, true
+	}, func() { time.Sleep(time.Second) })
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	for _, out := range RoundRobinSplit(ctx, ch, 2) {
+		if values := ToSlice(context.TODO(), out); values != nil {
+			t.Errorf("unexpected non-nil slice: %#v", values)
+		}
+	}
+}
+
+func TestPartition(t *testing.T) {
+	t.Parallel()
+	ch := startGenerator(t, 0, func(p int) (int, bool) {
+		if p > 5 {
+			return p, false
+		}
+		return p + 1, true
+	}, nil)
+
+	evens, odds := Partition(context.TODO(), ch, func(v int) bool { return v%2 == 0 })
+	gotEvens, gotOdds := collectBoth(evens, odds)
+
+	if expected := []int{2, 4, 6}; !reflect.DeepEqual(gotEvens, expected) {
+		t.Errorf("wrong matched values\nwant %#v\ngot  %#v", expected, gotEvens)
+	}
+	if expected := []int{1, 3, 5}; !reflect.DeepEqual(gotOdds, expected) {
+		t.Errorf("wrong unmatched values\nwant %#v\ngot  %#v", expected, gotOdds)
+	}
+}
+
+func TestPartitionWithContextCancellation(t *testing.T) {
+	t.Parallel()
+	ch := startGenerator(t, 0, func(p int) (int, bool) {
+		return p + 1, true
+	}, func() { time.Sleep(time.Second) })
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	evens, odds := Partition(ctx, ch, func(v int) bool { return v%2 == 0 })
+	gotEvens, gotOdds := collectBoth(evens, odds)
+	if gotEvens != nil || gotOdds != nil {
+		t.Errorf("unexpected values: %#v, %#v", gotEvens, gotOdds)
+	}
+}
+
+func collectBoth[A, B any](a <-chan A, b <-chan B) ([]A, []B) {
+	var (
+		gotA []A
+		gotB []B
+	)
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		gotA = ToSlice(context.TODO(), a)
+	}()
+	go func() {
+		defer wg.Done()
+		gotB = ToSlice(context.TODO(), b)
+	}()
+	wg.Wait()
+	return gotA, gotB
+}
+
+func TestFairMerge(t *testing.T) {
+	t.Parallel()
+	// buffered returns a closed channel with the provided values buffered, so
+	// it always has a value ready until it's drained.
+	buffered := func(values ...string) <-chan string {
+		ch := make(chan string, len(values))
+		for _, v := range values {
+			ch <- v
+		}
+		close(ch)
+		return ch
+	}
+
+	var tests = []struct {
+		name     string
+		ins      []<-chan string
+		expected []string
+	}{
+		{
+			name:     "alternates between ready inputs",
+			ins:      []<-chan string{buffered("a1", "a2", "a3"), buffered("b1", "b2", "b3")},
+			expected: []string{"a1", "b1", "a2", "b2", "a3", "b3"},
+		},
+		{
+			name:     "skips closed inputs",
+			ins:      []<-chan string{buffered("a1"), buffered("b1", "b2", "b3"), buffered("c1", "c2")},
+			expected: []string{"a1", "b1", "c1", "b2", "c2", "b3"},
+		},
+		{
+			name:     "no inputs",
+			expected: nil,
+		},
+	}
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+			values := ToSlice(context.TODO(), FairMerge(context.TODO(), test.ins...))
+			if !reflect.DeepEqual(values, test.expected) {
+				t.Errorf("wrong values returned\nwant %#v\ngot  %#v", test.expected, values)
+			}
+		})
+	}
+}
+
+func TestFairMergeWaitsForSlowInputs(t *testing.T) {
+	t.Parallel()
+	a := fromSlice(t, []int{1, 2, 3})
+	b := fromSlice(t, []int{10, 20, 30})
+
+	values := ToSlice(context.TODO(), FairMerge(context.TODO(), a, b))
+	sort.Ints(values)
+	expected := []int{1, 2, 3, 10, 20, 30}
+	if !reflect.DeepEqual(values, expected) {
+		t.Errorf("wrong values returned\nwant %#v\ngot  %#v", expected, values)
+	}
+}
+
+func TestFairMergeWithContextCancellation(t *testing.T) {
+	t.Parallel()
+	ch := startGenerator(t, 0, func(p int) (int, bool) {
+		return p + 1, true
+	}, func() { time.Sleep(time.Second) })
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	values := ToSlice(context.TODO(), FairMerge(ctx, ch))
+	if values != nil {
+		t.Errorf("unexpected non-nil slice: %#v", values)
+	}
+}
+
+func TestMergePriority(t *testing.T) {
+	t.Parallel()
+	high := make(chan string, 3)
+	low := make(chan string, 3)
+	for i := 1; i <= 3; i++ {
+		high <- fmt.Sprintf("high %d", i)
+		low <- fmt.Sprintf("low %d", i)
+	}
+	close(high)
+	close(low)
+
+	values := ToSlice(context.TODO(), MergePriority(context.TODO(), high, low))
+	expected := []string{"high 1", "high 2", "high 3", "low 1", "low 2", "low 3"}
+	if !reflect.DeepEqual(values, expected) {
+		t.Errorf("wrong values returned\nwant %#v\ngot  %#v", expected, values)
+	}
+}
+
+func TestMergePriorityFallsBackToLow(t *testing.T) {
+	t.Parallel()
+	high := make(chan int)
+	defer close(high)
+	low := fromSlice(t, []int{1, 2, 3})
+
+	out := MergePriority(context.TODO(), high, low)
+	for _, expected := range []int{1, 2, 3} {
+		if got := <-out; got != expected {
+			t.Errorf("wrong value returned\nwant %d\ngot  %d", expected, got)
+		}
+	}
+}
+
+func TestMergePriorityWithContextCancellation(t *testing.T) {
+	t.Parallel()
+	gen := func() <-chan int {
+		return startGenerator(t, 0, func(p int) (int, bool) {
+			return p + 1, true
+		}, func() { time.Sleep(time.Second) })
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	values := ToSlice(context.TODO(), MergePriority(ctx, gen(), gen()))
+	if values != nil {
+		t.Errorf("unexpected non-nil slice: %#v", values)
+	}
+}
+
+func TestMergeDistinct(t *testing.T) {
+	t.Parallel()
+	a := fromSlice(t, []int{1, 2, 3, 4, 2})
+	b := fromSlice(t, []int{3, 4, 5, 6, 1})
+
+	values := ToSlice(context.TODO(), MergeDistinct(context.TODO(), a, b))
+	sort.Ints(values)
+	expected := []int{1, 2, 3, 4, 5, 6}
+	if !reflect.DeepEqual(values, expected) {
+		t.Errorf("wrong values returned\nwant %#v\ngot  %#v", expected, values)
+	}
+}
+
+func TestMergeDistinctWithContextCancellation(t *testing.T) {
+	t.Parallel()
+	ch := startGenerator(t, 0, func(p int) (int, bool) {
+		return p + 1, true
+	}, func() { time.Sleep(time.Second) })
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	values := ToSlice(context.TODO(), MergeDistinct(ctx, ch))
+	if values != nil {
+		t.Errorf("unexpected non-nil slice: %#v", values)
+	}
+}
+
+func TestMergeTagged(t *testing.T) {
+	t.Parallel()
+	letters := fromSlice(t, []string{"a", "b", "c"})
+	numbers := fromSlice(t, []string{"1", "2"})
+
+	values := ToSlice(context.TODO(), MergeTagged(context.TODO(), letters, numbers))
+	if len(values) != 5 {
+		t.Fatalf("wrong number of values returned\nwant 5\ngot  %d (%#v)", len(values), values)
+	}
+	bySource := map[int][]string{}
+	for _, v := range values {
+		bySource[v.Index] = append(bySource[v.Index], v.Value)
+	}
+	expected := map[int][]string{0: {"a", "b", "c"}, 1: {"1", "2"}}
+	if !reflect.DeepEqual(bySource, expected) {
+		t.Errorf("wrong values by source\nwant %#v\ngot  %#v", expected, bySource)
+	}
+}
+
+func TestMergeTaggedWithContextCancellation(t *testing.T) {
+	t.Parallel()
+	ch := startGenerator(t, "", func(v string) (string, bool) {
+		return v, true
+	}, func() { time.Sleep(time.Second) })
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	values := ToSlice(context.TODO(), MergeTagged(ctx, ch))
+	if values != nil {
+		t.Errorf("unexpected non-nil slice: %#v", values)
+	}
+}
+
+func TestSwitch(t *testing.T) {
+	t.Parallel()
+	in := fromSlice(t, []string{"apple", "banana", "cherry", "avocado", "date", "blueberry", "elderberry", "coconut"})
+	outs, other := Switch(context.TODO(), in, func(v string) byte { return v[0] }, []byte{'a', 'b', 'c'})
+	if len(outs) != 3 {
+		t.Fatalf("wrong number of output channels\nwant 3\ngot  %d", len(outs))
+	}
+
+	got := map[byte][]string{}
+	var (
+		mu sync.Mutex
+		wg sync.WaitGroup
+	)
+	collect := func(k byte, ch <-chan string) {
+		defer wg.Done()
+		values := ToSlice(context.TODO(), ch)
+		mu.Lock()
+		defer mu.Unlock()
+		got[k] = values
+	}
+	for k, ch := range outs {
+		wg.Add(1)
+		go collect(k, ch)
+	}
+	wg.Add(1)
+	go collect(0, other)
+	wg.Wait()
+
+	expected := map[byte][]string{
+		'a': {"apple", "avocado"},
+		'b': {"banana", "blueberry"},
+		'c': {"cherry", "coconut"},
+		0:   {"date", "elderberry"},
+	}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("wrong values routed\nwant %#v\ngot  %#v", expected, got)
+	}
+}
+
+func TestSwitchWithContextCancellation(t *testing.T) {
+	t.Parallel()
+	ch := startGenerator(t, 0, func(p int) (int, bool) {
+		return p + 1, true
+	}, func() { time.Sleep(time.Second) })
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	outs, other := Switch(ctx, ch, func(v int) int { return v % 2 }, []int{0})
+	if values := ToSlice(context.TODO(), outs[0]); values != nil {
+		t.Errorf("unexpected non-nil slice: %#v", values)
+	}
+	if values := ToSlice(context.TODO(), other); values != nil {
+		t.Errorf("unexpected non-nil slice: %#v", values)
+	}
+}
+
+func TestMergeErr(t *testing.T) {
+	t.Parallel()
+
+	t.Run("merges values from all sources", func(t *testing.T) {
+		t.Parallel()
+		source := func(values ...int) func(context.Context) (<-chan int, <-chan error) {
+			return func(context.Context) (<-chan int, <-chan error) {
+				errs := make(chan error)
+				close(errs)
+				return fromSlice(t, values), errs
+			}
+		}
+		out, errs := MergeErr(context.TODO(), source(1, 2), source(3, 4))
+		values, gotErrs := collectValuesAndErrors(out, errs)
+		sort.Ints(values)
+		expected := []int{1, 2, 3, 4}
+		if !reflect.DeepEqual(values, expected) {
+			t.Errorf("wrong values returned\nwant %#v\ngot  %#v", expected, values)
+		}
+		if gotErrs != nil {
+			t.Errorf("unexpected errors: %#v", gotErrs)
+		}
+	})
+
+	t.Run("cancels the other sources on the first error", func(t *testing.T) {
+		t.Parallel()
+		errFailed := errors.New("failed")
+		cancelled := make(chan struct{})
+		infinite := func(ctx context.Context) (<-chan int, <-chan error) {
+			values := make(chan int)
+			go func() {
+				defer close(values)
+				defer close(cancelled)
+				for Send(ctx, values, 1) {
+				}
+			}()
+			return values, nil
+		}
+		failing := func(ctx context.Context) (<-chan int, <-chan error) {
+			errs := make(chan error, 1)
+			errs <- errFailed
+			close(errs)
+			return nil, errs
+		}
+
+		out, errs := MergeErr(context.TODO(), infinite, failing)
+		_, gotErrs := collectValuesAndErrors(out, errs)
+		expected := []error{errFailed}
+		if !reflect.DeepEqual(gotErrs, expected) {
+			t.Errorf("wrong errors returned\nwant %#v\ngot  %#v", expected, gotErrs)
+		}
+		select {
+		case <-cancelled:
+		case <-time.After(time.Second):
+			t.Error("the context of the other source wasn't cancelled")
+		}
+	})
+}
+
+func TestMergeErrWithContextCancellation(t *testing.T) {
+	t.Parallel()
+	source := func(context.Context) (<-chan int, <-chan error) {
+		return startGenerator(t, 0, func(p int) (int, bool) {
+			return p + 1, true
+		}, func() { time.Sleep(time.Second) }), nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	out, errs := MergeErr(ctx, source, source)
+	values, gotErrs := collectValuesAndErrors(out, errs)
+	if values != nil || gotErrs != nil {
+		t.Errorf("unexpected non-nil slices: %#v, %#v", values, gotErrs)
+	}
+}
+
+func TestTeeLossy(t *testing.T) {
+	t.Parallel()
+	in := make(chan int, 2)
+	var reports []int
+	primary, secondary := TeeLossy(context.TODO(), in, WithDropCounter(func(dropped int) {
+		reports = append(reports, dropped)
+	}))
+
+	// each value is read from the primary channel before the next one is
+	// sent, so the secondary channel fills up after two values.
+	send := func(values ...int) {
+		for _, v := range values {
+			in <- v
+			if got := <-primary; got != v {
+				t.Fatalf("wrong value returned to primary\nwant %d\ngot  %d", v, got)
+			}
+		}
+	}
+	send(1, 2, 3, 4, 5)
+	for _, expected := range []int{1, 2} {
+		if got := <-secondary; got != expected {
+			t.Errorf("wrong value returned to secondary\nwant %d\ngot  %d", expected, got)
+		}
+	}
+	send(6, 7, 8, 9)
+	close(in)
+	if values := ToSlice(context.TODO(), primary); values != nil {
+		t.Errorf("unexpected values returned to primary: %#v", values)
+	}
+
+	expectedSecondary := []int{6, 7}
+	if got := ToSlice(context.TODO(), secondary); !reflect.DeepEqual(got, expectedSecondary) {
+		t.Errorf("wrong values returned to secondary\nwant %#v\ngot  %#v", expectedSecondary, got)
+	}
+	// 3, 4 and 5 are reported when 6 is forwarded, and 8 and 9 when the
+	// input channel is closed.
+	expectedReports := []int{3, 2}
+	if !reflect.DeepEqual(reports, expectedReports) {
+		t.Errorf("wrong drop counts reported\nwant %#v\ngot  %#v", expectedReports, reports)
+	}
+}
+
+func TestTeeLossyWithContextCancellation(t *testing.T) {
+	t.Parallel()
+	ch := startGenerator(t, 0, func(p int) (int, bool) {
+		return p + 1, true
+	}, func() { time.Sleep(time.Second) })
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	primary, secondary := TeeLossy(ctx, ch)
+	gotPrimary, gotSecondary := collectBoth(primary, secondary)
+	if gotPrimary != nil || gotSecondary != nil {
+		t.Errorf("unexpected non-nil slices: %#v, %#v", gotPrimary, gotSecondary)
+	}
+}
@@ -0,0 +1,249 @@
+package channels
+
+import (
+	"context"
+	"sync"
+)
+
+// DropPolicy defines what a Multicast does with a value when the buffer of a
+// subscriber is full.
+type DropPolicy int
+
+const (
+	// Block makes the Multicast wait until the subscriber has room for the
+	// value, applying backpressure to the input channel and, consequently,
+	// to all the other subscribers. This is the default policy, as it
+	// guarantees that every subscriber receives every value.
+	Block DropPolicy = iota
+
+	// DropNewest discards the value for the subscriber that doesn't have
+	// room for it, keeping the values that are already buffered.
+	DropNewest
+
+	// DropOldest discards the oldest value buffered for the subscriber that
+	// doesn't have room for the new value, so the subscriber always sees the
+	// most recent values.
+	DropOldest
+)
+
+// MulticastOption is an option that can be provided to NewMulticast.
+type MulticastOption func(*multicastOptions)
+
+type multicastOptions struct {
+	policy     DropPolicy
+	bufferSize int
+	replaySize int
+}
+
+// WithDropPolicy sets the policy used by a Multicast when a subscriber can't
+// keep up with the input channel.
+//
+// The default value is Block.
+func WithDropPolicy(policy DropPolicy) MulticastOption {
+	return func(opts *multicastOptions) {
+		opts.policy = policy
+	}
+}
+
+// WithSubscriberBufferSize sets the capacity of the channels returned by
+// Multicast.Subscribe. With the lossy drop policies, this is the number of
+// values a subscriber can fall behind before values are discarded, so sizes
+// lower than 1 are treated as 1 with those policies.
+//
+// The default value is cap(inputChannel).
+func WithSubscriberBufferSize(n int) MulticastOption {
+	return func(opts *multicastOptions) {
+		opts.bufferSize = n
+	}
+}
+
+// WithReplayBuffer makes the Multicast keep the last n values received from
+// the input channel, so that new subscribers receive them before any live
+// values. The channels returned by Multicast.Subscribe have room for the
+// replayed values in addition to the subscriber buffer size.
+//
+// The default value is 0, meaning that subscribers only receive values
+// received after they subscribe.
+func WithReplayBuffer(n int) MulticastOption {
+	return func(opts *multicastOptions) {
+		opts.replaySize = n
+	}
+}
+
+// Multicast delivers each value from an input channel to all of its
+// subscribers. Values received while there are no subscribers are discarded,
+// unless they're kept in the replay buffer (see WithReplayBuffer).
+type Multicast[T any] struct {
+	opts multicastOptions
+	done chan struct{}
+
+	mu          sync.Mutex
+	closed      bool
+	subscribers []*subscriber[T]
+	replay      []T
+	replayNext  int
+}
+
+type subscriber[T any] struct {
+	ctx context.Context
+	ch  chan T
+
+	// mu guards sends to ch against closing it, so that the Multicast
+	// doesn't hold its own lock while waiting for a blocked subscriber.
+	mu     sync.Mutex
+	closed bool
+}
+
+func (s *subscriber[T]) close() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.closed {
+		s.closed = true
+		close(s.ch)
+	}
+}
+
+// NewMulticast creates a Multicast that consumes the provided input channel
+// and delivers its values to subscribers, according to the provided options.
+//
+// This is a non-blocking function: it launches a goroutine and returns the
+// Multicast for subscription. In order to stop the inner goroutine, one can
+// close the input channel or cancel the provided context.
+//
+// The channels of all subscribers are always closed on cancellation, even if
+// the input channel is never closed.
+func NewMulticast[T any](ctx context.Context, in <-chan T, opts ...MulticastOption) *Multicast[T] {
+	options := multicastOptions{policy: Block, bufferSize: cap(in)}
+	for _, opt := range opts {
+		opt(&options)
+	}
+	if options.policy != Block && options.bufferSize < 1 {
+		options.bufferSize = 1
+	}
+	m := &Multicast[T]{opts: options, done: make(chan struct{})}
+	go m.run(ctx, in)
+	return m
+}
+
+// Subscribe returns a channel that will receive values from the input channel
+// of the Multicast, starting with the next value received, preceded by the
+// values kept in the replay buffer, if any (see WithReplayBuffer).
+//
+// The returned channel is closed when the provided context is cancelled, when
+// the input channel is closed or when the context of the Multicast is
+// cancelled. Subscribing after that returns a channel that only receives the
+// values kept in the replay buffer before it's closed.
+func (m *Multicast[T]) Subscribe(ctx context.Context) <-chan T {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	sub := &subscriber[T]{ctx: ctx, ch: make(chan T, m.opts.bufferSize+len(m.replay))}
+	for i := range m.replay {
+		sub.ch <- m.replay[(m.replayNext+i)%len(m.replay)]
+	}
+	if m.closed {
+		close(sub.ch)
+		return sub.ch
+	}
+	m.subscribers = append(m.subscribers, sub)
+	go func() {
+		select {
+		case <-ctx.Done():
+			m.unsubscribe(sub)
+		case <-m.done:
+		}
+	}()
+	return sub.ch
+}
+
+func (m *Multicast[T]) unsubscribe(sub *subscriber[T]) {
+	m.mu.Lock()
+	for i, s := range m.subscribers {
+		if s == sub {
+			// The list is copied rather than modified in place, as run
+			// may be delivering to the previous one.
+			subscribers := make([]*subscriber[T], 0, len(m.subscribers)-1)
+			subscribers = append(subscribers, m.subscribers[:i]...)
+			m.subscribers = append(subscribers, m.subscribers[i+1:]...)
+			break
+		}
+	}
+	m.mu.Unlock()
+	sub.close()
+}
+
+func (m *Multicast[T]) run(ctx context.Context, in <-chan T) {
+	receiveLoop(ctx, in, func(v T) bool {
+		m.mu.Lock()
+		m.record(v)
+		subscribers := m.subscribers
+		m.mu.Unlock()
+		for _, sub := range subscribers {
+			if !m.deliver(ctx, sub, v) {
+				return false
+			}
+		}
+		return true
+	})
+
+	m.mu.Lock()
+	m.closed = true
+	subscribers := m.subscribers
+	m.subscribers = nil
+	close(m.done)
+	m.mu.Unlock()
+	for _, sub := range subscribers {
+		sub.close()
+	}
+}
+
+// record keeps the value in the replay buffer, evicting the oldest value once
+// the buffer is full.
+func (m *Multicast[T]) record(v T) {
+	if len(m.replay) < m.opts.replaySize {
+		m.replay = append(m.replay, v)
+	} else if len(m.replay) > 0 {
+		m.replay[m.replayNext] = v
+		m.replayNext = (m.replayNext + 1) % len(m.replay)
+	}
+}
+
+// deliver sends the value to the subscriber according to the drop policy. It
+// returns false if the context of the Multicast is cancelled while waiting
+// for a blocked subscriber.
+func (m *Multicast[T]) deliver(ctx context.Context, sub *subscriber[T], v T) bool {
+	sub.mu.Lock()
+	defer sub.mu.Unlock()
+	if sub.closed {
+		return true
+	}
+	switch m.opts.policy {
+	case DropNewest:
+		select {
+		case sub.ch <- v:
+		default:
+		}
+	case DropOldest:
+		select {
+		case sub.ch <- v:
+		default:
+			// The Multicast is the only sender, so after taking the
+			// oldest value out, there's room for the new one.
+			select {
+			case <-sub.ch:
+			default:
+			}
+			select {
+			case sub.ch <- v:
+			default:
+			}
+		}
+	default:
+		select {
+		case sub.ch <- v:
+		case <-sub.ctx.Done():
+		case <-ctx.Done():
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,259 @@
+package channels
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestMulticast(t *testing.T) {
+	t.Parallel()
+	ch := startGenerator(t, 0, func(p int) (int, bool) {
+		if p > 4 {
+			return p, false
+		}
+		return p + 1, true
+	}, func() { time.Sleep(10 * time.Millisecond) })
+
+	m := NewMulticast(context.TODO(), ch)
+	sub1 := m.Subscribe(context.TODO())
+	sub2 := m.Subscribe(context.TODO())
+
+	results := make(chan []int)
+	go func() { results <- ToSlice(context.TODO(), sub1) }()
+	go func() { results <- ToSlice(context.TODO(), sub2) }()
+
+	expected := []int{1, 2, 3, 4, 5}
+	for i := 0; i < 2; i++ {
+		if got := <-results; !reflect.DeepEqual(got, expected) {
+			t.Errorf("wrong values returned\nwant %#v\ngot  %#v", expected, got)
+		}
+	}
+
+	if values := ToSlice(context.TODO(), m.Subscribe(context.TODO())); values != nil {
+		t.Errorf("unexpected values after the input channel is closed: %#v", values)
+	}
+}
+
+func TestMulticastUnsubscribe(t *testing.T) {
+	t.Parallel()
+	ch := startGenerator(t, 0, func(p int) (int, bool) {
+		return p + 1, true
+	}, func() { time.Sleep(10 * time.Millisecond) })
+
+	m := NewMulticast(context.TODO(), ch)
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	sub := m.Subscribe(ctx)
+	other := m.Subscribe(context.TODO())
+	go Consume(context.TODO(), other, func(int) bool { return true })
+
+	values := ToSlice(context.TODO(), sub)
+	if len(values) == 0 {
+		t.Fatal("unexpected empty slice")
+	}
+}
+
+func TestMulticastDropPolicy(t *testing.T) {
+	t.Parallel()
+
+	// send sends each value to the input channel and then reads it from the
+	// fast subscriber, while the slow subscriber never reads.
+	send := func(t *testing.T, in chan<- int, fast <-chan int, values ...int) {
+		t.Helper()
+		for _, v := range values {
+			in <- v
+			if got := <-fast; got != v {
+				t.Fatalf("wrong value received by fast subscriber\nwant %d\ngot  %d", v, got)
+			}
+		}
+	}
+
+	t.Run("Block", func(t *testing.T) {
+		in := make(chan int)
+		m := NewMulticast(context.TODO(), in, WithSubscriberBufferSize(2))
+		slow := m.Subscribe(context.TODO())
+		fast := m.Subscribe(context.TODO())
+
+		send(t, in, fast, 1, 2)
+		in <- 3
+		select {
+		case in <- 4:
+			t.Fatal("unexpected send while the slow subscriber is full")
+		case <-time.After(100 * time.Millisecond):
+		}
+
+		expectedSlow := []int{1, 2, 3, 4}
+		go func() {
+			in <- 4
+			close(in)
+		}()
+		if got := ToSlice(context.TODO(), slow); !reflect.DeepEqual(got, expectedSlow) {
+			t.Errorf("wrong values returned to slow subscriber\nwant %#v\ngot  %#v", expectedSlow, got)
+		}
+		expectedFast := []int{3, 4}
+		if got := ToSlice(context.TODO(), fast); !reflect.DeepEqual(got, expectedFast) {
+			t.Errorf("wrong values returned to fast subscriber\nwant %#v\ngot  %#v", expectedFast, got)
+		}
+	})
+
+	t.Run("DropNewest", func(t *testing.T) {
+		in := make(chan int)
+		m := NewMulticast(context.TODO(), in, WithSubscriberBufferSize(2), WithDropPolicy(DropNewest))
+		slow := m.Subscribe(context.TODO())
+		fast := m.Subscribe(context.TODO())
+
+		send(t, in, fast, 1, 2, 3, 4, 5)
+		close(in)
+
+		expected := []int{1, 2}
+		if got := ToSlice(context.TODO(), slow); !reflect.DeepEqual(got, expected) {
+			t.Errorf("wrong values returned to slow subscriber\nwant %#v\ngot  %#v", expected, got)
+		}
+	})
+
+	t.Run("DropOldest", func(t *testing.T) {
+		in := make(chan int)
+		m := NewMulticast(context.TODO(), in, WithSubscriberBufferSize(2), WithDropPolicy(DropOldest))
+		slow := m.Subscribe(context.TODO())
+		fast := m.Subscribe(context.TODO())
+
+		send(t, in, fast, 1, 2, 3, 4, 5)
+		close(in)
+
+		expected := []int{4, 5}
+		if got := ToSlice(context.TODO(), slow); !reflect.DeepEqual(got, expected) {
+			t.Errorf("wrong values returned to slow subscriber\nwant %#v\ngot  %#v", expected, got)
+		}
+	})
+}
+
+func TestMulticastLossyPolicyWithUnbufferedInput(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		policy   DropPolicy
+		name     string
+		expected []int
+	}{
+		{policy: DropNewest, name: "DropNewest", expected: []int{1}},
+		{policy: DropOldest, name: "DropOldest", expected: []int{3}},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+			in := make(chan int)
+			m := NewMulticast(context.TODO(), in, WithDropPolicy(test.policy))
+			slow := m.Subscribe(context.TODO())
+			fast := m.Subscribe(context.TODO())
+			go Consume(context.TODO(), fast, func(int) bool { return true })
+
+			// the subscriber buffer defaults to 1 with the lossy
+			// policies, so the slow subscriber keeps one value.
+			in <- 1
+			in <- 2
+			in <- 3
+			close(in)
+			if got := ToSlice(context.TODO(), slow); !reflect.DeepEqual(got, test.expected) {
+				t.Errorf("wrong values returned to slow subscriber\nwant %#v\ngot  %#v", test.expected, got)
+			}
+		})
+	}
+}
+
+func TestMulticastSubscribeWhileBlocked(t *testing.T) {
+	t.Parallel()
+	in := make(chan int)
+	m := NewMulticast(context.TODO(), in)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	blocked := m.Subscribe(ctx)
+
+	// the Multicast receives 1 and waits for the blocked subscriber, which
+	// must not prevent subscribing and unsubscribing.
+	in <- 1
+	subscribed := make(chan (<-chan int))
+	go func() { subscribed <- m.Subscribe(context.TODO()) }()
+	var late <-chan int
+	select {
+	case late = <-subscribed:
+	case <-time.After(time.Second):
+		t.Fatal("Subscribe blocked by a blocked subscriber")
+	}
+
+	cancel()
+	if values := ToSlice(context.TODO(), blocked); values != nil {
+		t.Errorf("unexpected values returned to blocked subscriber: %#v", values)
+	}
+	go func() {
+		in <- 2
+		close(in)
+	}()
+	expected := []int{2}
+	if got := ToSlice(context.TODO(), late); !reflect.DeepEqual(got, expected) {
+		t.Errorf("wrong values returned to late subscriber\nwant %#v\ngot  %#v", expected, got)
+	}
+}
+
+func TestMulticastWithContextCancellation(t *testing.T) {
+	t.Parallel()
+	ch := startGenerator(t, "", func(v string) (string, bool) {
+		return v, true
+	}, func() { time.Sleep(time.Second) })
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	m := NewMulticast(ctx, ch)
+	values := ToSlice(context.TODO(), m.Subscribe(context.TODO()))
+	if values != nil {
+		t.Errorf("unexpected non-nil slice: %#v", values)
+	}
+}
+
+func TestMulticastReplayBuffer(t *testing.T) {
+	t.Parallel()
+	in := make(chan int)
+	m := NewMulticast(context.TODO(), in, WithReplayBuffer(2))
+	early := m.Subscribe(context.TODO())
+
+	// reading from the early subscriber guarantees that the Multicast has
+	// processed each value before the late subscriber joins.
+	for i := 1; i <= 3; i++ {
+		in <- i
+		<-early
+	}
+	late := m.Subscribe(context.TODO())
+	go Consume(context.TODO(), early, func(int) bool { return true })
+
+	go func() {
+		in <- 4
+		in <- 5
+		close(in)
+	}()
+	expected := []int{2, 3, 4, 5}
+	if got := ToSlice(context.TODO(), late); !reflect.DeepEqual(got, expected) {
+		t.Errorf("wrong values returned\nwant %#v\ngot  %#v", expected, got)
+	}
+}
+
+func TestMulticastReplayBufferAfterClose(t *testing.T) {
+	t.Parallel()
+	in := make(chan int)
+	m := NewMulticast(context.TODO(), in, WithReplayBuffer(2))
+	early := m.Subscribe(context.TODO())
+	go func() {
+		defer close(in)
+		for i := 1; i <= 3; i++ {
+			in <- i
+		}
+	}()
+
+	// the early subscriber is closed once the Multicast is done.
+	ToSlice(context.TODO(), early)
+	expected := []int{2, 3}
+	if got := ToSlice(context.TODO(), m.Subscribe(context.TODO())); !reflect.DeepEqual(got, expected) {
+		t.Errorf("wrong values returned\nwant %#v\ngot  %#v", expected, got)
+	}
+}
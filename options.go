@@ -0,0 +1,202 @@
+package channels
+
+import (
+	"context"
+	"sync"
+)
+
+// OperatorOption is an option that can be provided to operators to configure
+// their output channel. It's accepted by every operator that takes options for
+// its output channel, including the ones that take a MapOption, an
+// ErrorOption or a FilterErrorOption.
+type OperatorOption interface {
+	MapOption
+	ErrorOption
+}
+
+// MapOption is an option that can be provided to Map, MapCtx, FilterMap and
+// FilterMapIndex.
+type MapOption interface {
+	apply(*operatorOptions)
+	isMapOption()
+}
+
+// ErrorOption is an option that can be provided to operators that send
+// errors to a separate channel, such as MapError, FilterError and Checkpoint.
+type ErrorOption interface {
+	FilterErrorOption
+	isErrorOption()
+}
+
+// FilterErrorOption is an option that can be provided to FilterError.
+type FilterErrorOption interface {
+	apply(*operatorOptions)
+	isFilterErrorOption()
+}
+
+type operatorOptions struct {
+	bufferGauge func(len, cap int)
+	outputCap   int
+	hasCap      bool
+	recoverFunc func(recovered any) (skip bool)
+	errorBuffer int
+	keepOnError bool
+	contextFunc func(ctx context.Context, elementIndex int) context.Context
+}
+
+type operatorOption func(*operatorOptions)
+
+func (o operatorOption) apply(opts *operatorOptions) {
+	o(opts)
+}
+
+func (operatorOption) isMapOption() {}
+
+func (operatorOption) isErrorOption() {}
+
+func (operatorOption) isFilterErrorOption() {}
+
+type mapOption func(*operatorOptions)
+
+func (o mapOption) apply(opts *operatorOptions) {
+	o(opts)
+}
+
+func (mapOption) isMapOption() {}
+
+type errorOption func(*operatorOptions)
+
+func (o errorOption) apply(opts *operatorOptions) {
+	o(opts)
+}
+
+func (errorOption) isErrorOption() {}
+
+func (errorOption) isFilterErrorOption() {}
+
+type filterErrorOption func(*operatorOptions)
+
+func (o filterErrorOption) apply(opts *operatorOptions) {
+	o(opts)
+}
+
+func (filterErrorOption) isFilterErrorOption() {}
+
+// WithBufferGauge sets a function that the operator calls after each value
+// sent to its output channel, with the current length and capacity of the
+// output channel. It can be used to observe how full the buffer of the output
+// channel gets, which indicates backpressure from slow consumers.
+//
+// The function is called from the goroutines of the operator, so it should not
+// block. Calls are never concurrent, even for operators that send values from
+// several goroutines, such as MergeWithOptions and FanOut.
+func WithBufferGauge(gauge func(len, cap int)) OperatorOption {
+	return operatorOption(func(opts *operatorOptions) {
+		opts.bufferGauge = gauge
+	})
+}
+
+// WithOutputCap sets the capacity of the output channels of the operator,
+// instead of the default capacity documented by each operator. Capacities
+// lower than 0 are treated as 0.
+func WithOutputCap(n int) OperatorOption {
+	return operatorOption(func(opts *operatorOptions) {
+		if n < 0 {
+			n = 0
+		}
+		opts.outputCap = n
+		opts.hasCap = true
+	})
+}
+
+// WithRecover makes the operator recover from panics in the function provided
+// to it, calling the handler with the recovered value. If the handler returns
+// true, the value that caused the panic is skipped and the operator carries
+// on with the next value, otherwise the operator stops and its output channel
+// is closed. Without this option, panics are propagated.
+func WithRecover(handler func(recovered any) (skip bool)) MapOption {
+	return mapOption(func(opts *operatorOptions) {
+		opts.recoverFunc = handler
+	})
+}
+
+// WithErrorBuffer sets the capacity of the error channel of the operator, so
+// that up to n errors can be buffered without blocking the operator while the
+// error channel isn't being read. Capacities lower than 0 are treated as 0.
+func WithErrorBuffer(n int) ErrorOption {
+	return errorOption(func(opts *operatorOptions) {
+		if n < 0 {
+			n = 0
+		}
+		opts.errorBuffer = n
+	})
+}
+
+// WithKeepOnError makes FilterError forward the values for which the predicate
+// returns an error, in addition to sending the error to the error channel.
+// Without this option, such values are discarded.
+func WithKeepOnError() FilterErrorOption {
+	return filterErrorOption(func(opts *operatorOptions) {
+		opts.keepOnError = true
+	})
+}
+
+// capacity returns the capacity set with WithOutputCap, or the provided
+// default capacity.
+func (o operatorOptions) capacity(defaultCap int) int {
+	if o.hasCap {
+		return o.outputCap
+	}
+	return defaultCap
+}
+
+func newOperatorOptions[O interface{ apply(*operatorOptions) }](opts []O) operatorOptions {
+	var options operatorOptions
+	for _, opt := range opts {
+		opt.apply(&options)
+	}
+	return options
+}
+
+// serializeGauge returns a copy of the options whose buffer gauge is guarded by
+// a mutex, for operators that call sendObserved from several goroutines.
+func (o operatorOptions) serializeGauge() operatorOptions {
+	if gauge := o.bufferGauge; gauge != nil {
+		var mu sync.Mutex
+		o.bufferGauge = func(len, cap int) {
+			mu.Lock()
+			defer mu.Unlock()
+			gauge(len, cap)
+		}
+	}
+	return o
+}
+
+// sendObserved sends the value to the output channel, like trySend, and
+// reports the occupancy of the output channel to the buffer gauge, if there's
+// one.
+func sendObserved[T any](ctx context.Context, out chan T, v T, options operatorOptions) bool {
+	if !trySend(ctx, out, v) {
+		return false
+	}
+	if options.bufferGauge != nil {
+		options.bufferGauge(len(out), cap(out))
+	}
+	return true
+}
+
+// callRecovering calls f with the value, recovering from panics according to
+// the handler set with WithRecover, if there's one. The last return value is
+// false if the operator should stop.
+func callRecovering[InputType, OutputType any](f func(InputType) (OutputType, bool), v InputType, options operatorOptions) (outValue OutputType, ok bool, cont bool) {
+	if options.recoverFunc != nil {
+		defer func() {
+			if r := recover(); r != nil {
+				var zero OutputType
+				outValue, ok, cont = zero, false, options.recoverFunc(r)
+			}
+		}()
+	}
+	outValue, ok = f(v)
+	return outValue, ok, true
+}
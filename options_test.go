@@ -0,0 +1,190 @@
+package channels
+
+import (
+	"context"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestWithBufferGauge(t *testing.T) {
+	t.Parallel()
+	var (
+		mu       sync.Mutex
+		maxLen   int
+		gotCap   int
+		observed int
+	)
+	gauge := WithBufferGauge(func(l, c int) {
+		mu.Lock()
+		defer mu.Unlock()
+		observed++
+		gotCap = c
+		if l > maxLen {
+			maxLen = l
+		}
+	})
+
+	tests := []struct {
+		name string
+		run  func(<-chan int) <-chan int
+	}{
+		{name: "Filter", run: func(ch <-chan int) <-chan int {
+			return Filter(context.TODO(), ch, func(int) bool { return true }, gauge)
+		}},
+		{name: "Map", run: func(ch <-chan int) <-chan int {
+			return Map(context.TODO(), ch, func(v int) int { return v }, gauge)
+		}},
+		{name: "FilterMap", run: func(ch <-chan int) <-chan int {
+			return FilterMap(context.TODO(), ch, func(v int) (int, bool) { return v, true }, gauge)
+		}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			mu.Lock()
+			maxLen, gotCap, observed = 0, 0, 0
+			mu.Unlock()
+
+			in := make(chan int, 5)
+			for i := 1; i <= 5; i++ {
+				in <- i
+			}
+			close(in)
+			out := test.run(in)
+
+			// Slow consumer: give the operator time to fill the buffer.
+			time.Sleep(50 * time.Millisecond)
+			values := ToSlice(context.TODO(), out)
+			if len(values) != 5 {
+				t.Fatalf("wrong number of values returned\nwant 5\ngot  %d", len(values))
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+			if observed != 5 {
+				t.Errorf("wrong number of observations\nwant 5\ngot  %d", observed)
+			}
+			if gotCap != 5 {
+				t.Errorf("wrong capacity observed\nwant 5\ngot  %d", gotCap)
+			}
+			if maxLen == 0 {
+				t.Error("gauge didn't observe non-zero occupancy")
+			}
+		})
+	}
+}
+
+func TestWithOutputCap(t *testing.T) {
+	t.Parallel()
+	in := make(chan int, 3)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	t.Run("Filter", func(t *testing.T) {
+		if c := cap(Filter(ctx, in, func(int) bool { return true })); c != 3 {
+			t.Errorf("wrong default capacity\nwant 3\ngot  %d", c)
+		}
+		if c := cap(Filter(ctx, in, func(int) bool { return true }, WithOutputCap(10))); c != 10 {
+			t.Errorf("wrong capacity\nwant 10\ngot  %d", c)
+		}
+	})
+
+	t.Run("Merge", func(t *testing.T) {
+		ins := []<-chan int{in, make(chan int, 5)}
+		if c := cap(Merge(ctx, ins...)); c != 0 {
+			t.Errorf("wrong default capacity\nwant 0\ngot  %d", c)
+		}
+		if c := cap(MergeWithOptions(ctx, ins, WithOutputCap(10))); c != 10 {
+			t.Errorf("wrong capacity\nwant 10\ngot  %d", c)
+		}
+	})
+
+	t.Run("FanOut", func(t *testing.T) {
+		for _, out := range FanOut(ctx, in, 2) {
+			if c := cap(out); c != 3 {
+				t.Errorf("wrong default capacity\nwant 3\ngot  %d", c)
+			}
+		}
+		for _, out := range FanOut(ctx, in, 2, WithOutputCap(10)) {
+			if c := cap(out); c != 10 {
+				t.Errorf("wrong capacity\nwant 10\ngot  %d", c)
+			}
+		}
+	})
+
+	t.Run("Partition", func(t *testing.T) {
+		matched, unmatched := Partition(ctx, in, func(int) bool { return true })
+		if cap(matched) != 3 || cap(unmatched) != 3 {
+			t.Errorf("wrong default capacities\nwant 3, 3\ngot  %d, %d", cap(matched), cap(unmatched))
+		}
+		matched, unmatched = Partition(ctx, in, func(int) bool { return true }, WithOutputCap(10))
+		if cap(matched) != 10 || cap(unmatched) != 10 {
+			t.Errorf("wrong capacities\nwant 10, 10\ngot  %d, %d", cap(matched), cap(unmatched))
+		}
+	})
+
+	t.Run("combined with operator-specific options", func(t *testing.T) {
+		out := Map(ctx, in, func(v int) int { return v }, WithOutputCap(10), WithRecover(func(any) bool { return true }))
+		if c := cap(out); c != 10 {
+			t.Errorf("wrong capacity for Map\nwant 10\ngot  %d", c)
+		}
+		mapped, mapErrs := MapError(ctx, in, func(v int) (int, error) { return v, nil }, WithOutputCap(10), WithErrorBuffer(2))
+		if cap(mapped) != 10 || cap(mapErrs) != 2 {
+			t.Errorf("wrong capacities for MapError\nwant 10, 2\ngot  %d, %d", cap(mapped), cap(mapErrs))
+		}
+		filtered, filterErrs := FilterError(ctx, in, func(int) (bool, error) { return true, nil }, WithOutputCap(10), WithErrorBuffer(2), WithKeepOnError())
+		if cap(filtered) != 10 || cap(filterErrs) != 2 {
+			t.Errorf("wrong capacities for FilterError\nwant 10, 2\ngot  %d, %d", cap(filtered), cap(filterErrs))
+		}
+		checkpointed, checkpointErrs := Checkpoint(ctx, in, 1, func(int, int) error { return nil }, WithOutputCap(10), WithErrorBuffer(2))
+		if cap(checkpointed) != 10 || cap(checkpointErrs) != 2 {
+			t.Errorf("wrong capacities for Checkpoint\nwant 10, 2\ngot  %d, %d", cap(checkpointed), cap(checkpointErrs))
+		}
+	})
+
+	t.Run("negative capacity", func(t *testing.T) {
+		if c := cap(Filter(ctx, in, func(int) bool { return true }, WithOutputCap(-1))); c != 0 {
+			t.Errorf("wrong capacity\nwant 0\ngot  %d", c)
+		}
+	})
+}
+
+func TestWithRecover(t *testing.T) {
+	t.Parallel()
+	f := func(v int) int {
+		if v == 3 {
+			panic("bad input")
+		}
+		return v * 10
+	}
+
+	t.Run("skip", func(t *testing.T) {
+		t.Parallel()
+		var recovered []any
+		out := Map(context.TODO(), fromSlice(t, []int{1, 2, 3, 4, 5}), f, WithRecover(func(r any) bool {
+			recovered = append(recovered, r)
+			return true
+		}))
+		values := ToSlice(context.TODO(), out)
+		expected := []int{10, 20, 40, 50}
+		if !reflect.DeepEqual(values, expected) {
+			t.Errorf("wrong values returned\nwant %#v\ngot  %#v", expected, values)
+		}
+		if expectedRecovered := []any{"bad input"}; !reflect.DeepEqual(recovered, expectedRecovered) {
+			t.Errorf("wrong values recovered\nwant %#v\ngot  %#v", expectedRecovered, recovered)
+		}
+	})
+
+	t.Run("stop", func(t *testing.T) {
+		t.Parallel()
+		out := Map(context.TODO(), fromSlice(t, []int{1, 2, 3, 4, 5}), f, WithRecover(func(any) bool {
+			return false
+		}))
+		values := ToSlice(context.TODO(), out)
+		expected := []int{10, 20}
+		if !reflect.DeepEqual(values, expected) {
+			t.Errorf("wrong values returned\nwant %#v\ngot  %#v", expected, values)
+		}
+	})
+}
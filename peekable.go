@@ -0,0 +1,92 @@
+package channels
+
+import "context"
+
+// Peekable wraps a channel to support looking at the next value without
+// consuming it.
+//
+// A Peekable is not safe for concurrent use by multiple goroutines.
+type Peekable[T any] struct {
+	ctx      context.Context
+	in       <-chan T
+	next     T
+	buffered bool
+}
+
+// NewPeekable returns a Peekable that reads from the provided input channel.
+// The provided context is used to abort calls to Peek and Next that are
+// waiting for a value.
+func NewPeekable[T any](ctx context.Context, in <-chan T) *Peekable[T] {
+	return &Peekable[T]{ctx: ctx, in: in}
+}
+
+// Peek returns the next value without consuming it, so the following call to
+// Peek or Next returns the same value. The boolean return value is false if
+// the input channel is closed or the context is cancelled before a value is
+// available.
+//
+// This is a blocking function.
+func (p *Peekable[T]) Peek() (T, bool) {
+	if !p.buffered {
+		select {
+		case v, ok := <-p.in:
+			if !ok {
+				return v, false
+			}
+			p.next = v
+			p.buffered = true
+		case <-p.ctx.Done():
+			var zero T
+			return zero, false
+		}
+	}
+	return p.next, true
+}
+
+// Next consumes and returns the next value, which is the value returned by
+// the previous call to Peek, if there was one. The boolean return value is
+// false if the input channel is closed or the context is cancelled before a
+// value is available.
+//
+// This is a blocking function.
+func (p *Peekable[T]) Next() (T, bool) {
+	v, ok := p.Peek()
+	if ok {
+		var zero T
+		p.next = zero
+		p.buffered = false
+	}
+	return v, ok
+}
+
+// Paginate returns a function that consumes the input channel one page at a
+// time: each call returns the next pageSize values, or fewer if the input
+// channel is closed or the provided context is cancelled before that, along
+// with a boolean that reports whether more values remain. To tell whether
+// more values remain, each call waits for the first value of the next page,
+// which is kept for the next call. If pageSize is lower than 1, it's treated
+// as 1.
+//
+// The returned function is not safe for concurrent use by multiple
+// goroutines.
+//
+// Each call to the returned function blocks until the page is complete, the
+// input channel is closed or the provided context is cancelled.
+func Paginate[T any](ctx context.Context, in <-chan T, pageSize int) func() ([]T, bool) {
+	if pageSize < 1 {
+		pageSize = 1
+	}
+	p := NewPeekable(ctx, in)
+	return func() ([]T, bool) {
+		var page []T
+		for len(page) < pageSize {
+			v, ok := p.Next()
+			if !ok {
+				return page, false
+			}
+			page = append(page, v)
+		}
+		_, more := p.Peek()
+		return page, more
+	}
+}
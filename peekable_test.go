@@ -0,0 +1,113 @@
+package channels
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestPeekable(t *testing.T) {
+	t.Parallel()
+	ch := startGenerator(t, 0, func(p int) (int, bool) {
+		if p > 3 {
+			return p, false
+		}
+		return p + 1, true
+	}, nil)
+	p := NewPeekable(context.TODO(), ch)
+
+	steps := []struct {
+		op       string
+		expected int
+		ok       bool
+	}{
+		{op: "peek", expected: 1, ok: true},
+		{op: "peek", expected: 1, ok: true},
+		{op: "next", expected: 1, ok: true},
+		{op: "next", expected: 2, ok: true},
+		{op: "peek", expected: 3, ok: true},
+		{op: "next", expected: 3, ok: true},
+		{op: "peek", expected: 4, ok: true},
+		{op: "next", expected: 4, ok: true},
+		{op: "peek", expected: 0, ok: false},
+		{op: "next", expected: 0, ok: false},
+	}
+	for i, step := range steps {
+		var (
+			v  int
+			ok bool
+		)
+		if step.op == "peek" {
+			v, ok = p.Peek()
+		} else {
+			v, ok = p.Next()
+		}
+		if v != step.expected || ok != step.ok {
+			t.Errorf("step %d (%s): wrong result\nwant %d, %t\ngot  %d, %t", i, step.op, step.expected, step.ok, v, ok)
+		}
+	}
+}
+
+func TestPeekableWithContextCancellation(t *testing.T) {
+	t.Parallel()
+	ch := startGenerator(t, "", func(v string) (string, bool) {
+		return v, true
+	}, func() { time.Sleep(time.Second) })
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	p := NewPeekable(ctx, ch)
+	if _, ok := p.Peek(); ok {
+		t.Error("unexpected value from Peek after cancellation")
+	}
+	if _, ok := p.Next(); ok {
+		t.Error("unexpected value from Next after cancellation")
+	}
+}
+
+func TestPaginate(t *testing.T) {
+	t.Parallel()
+	next := Paginate(context.TODO(), fromSlice(t, []int{1, 2, 3, 4, 5, 6, 7}), 3)
+
+	var tests = []struct {
+		expected     []int
+		expectedMore bool
+	}{
+		{[]int{1, 2, 3}, true},
+		{[]int{4, 5, 6}, true},
+		{[]int{7}, false},
+		{nil, false},
+	}
+	for i, test := range tests {
+		page, more := next()
+		if !reflect.DeepEqual(page, test.expected) || more != test.expectedMore {
+			t.Errorf("wrong page %d returned\nwant %#v, %t\ngot  %#v, %t", i, test.expected, test.expectedMore, page, more)
+		}
+	}
+}
+
+func TestPaginateExactPages(t *testing.T) {
+	t.Parallel()
+	next := Paginate(context.TODO(), fromSlice(t, []int{1, 2, 3, 4}), 2)
+	if page, more := next(); !reflect.DeepEqual(page, []int{1, 2}) || !more {
+		t.Errorf("wrong first page returned: %#v, %t", page, more)
+	}
+	if page, more := next(); !reflect.DeepEqual(page, []int{3, 4}) || more {
+		t.Errorf("wrong last page returned: %#v, %t", page, more)
+	}
+}
+
+func TestPaginateWithContextCancellation(t *testing.T) {
+	t.Parallel()
+	ch := startGenerator(t, 0, func(p int) (int, bool) {
+		return p + 1, true
+	}, func() { time.Sleep(10 * time.Millisecond) })
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	page, more := Paginate(ctx, ch, 1000)()
+	if len(page) == 0 || len(page) >= 1000 || more {
+		t.Errorf("unexpected page after cancellation: %d values, %t", len(page), more)
+	}
+}
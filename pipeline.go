@@ -0,0 +1,80 @@
+package channels
+
+import (
+	"context"
+	"sync"
+)
+
+// Pipeline coordinates the shutdown of a set of goroutines and operators that
+// share a context. Goroutines are started with Go and operators are added
+// with Stage, and Shutdown cancels the context of the pipeline and waits for
+// all of them to return.
+type Pipeline struct {
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// NewPipeline creates a Pipeline with a context derived from the provided
+// one.
+func NewPipeline(ctx context.Context) *Pipeline {
+	ctx, cancel := context.WithCancel(ctx)
+	return &Pipeline{ctx: ctx, cancel: cancel}
+}
+
+// Context returns the context of the pipeline, which is cancelled by
+// Shutdown.
+func (p *Pipeline) Context() context.Context {
+	return p.ctx
+}
+
+// Go runs f in a new goroutine tracked by the pipeline. The function receives
+// the context of the pipeline and must return once it's cancelled.
+func (p *Pipeline) Go(f func(ctx context.Context)) {
+	p.wg.Add(1)
+	go func() {
+		defer p.wg.Done()
+		f(p.ctx)
+	}()
+}
+
+// Shutdown cancels the context of the pipeline and waits for all goroutines
+// tracked by the pipeline to return. If the provided context is done before
+// that, Shutdown returns ctx.Err().
+func (p *Pipeline) Shutdown(ctx context.Context) error {
+	p.cancel()
+	done := make(chan struct{})
+	go func() {
+		p.wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Stage invokes the provided operator with the context of the pipeline and
+// the input channel, and returns a channel with the values of the output
+// channel of the operator. The pipeline tracks the operator by reading its
+// output channel until it's closed, which operators in this package only do
+// when their goroutines return.
+//
+// The output channel is unbuffered.
+//
+// The output channel is always closed once the output channel of the operator
+// is closed, including after Shutdown.
+func Stage[InputType, OutputType any](p *Pipeline, in <-chan InputType, op func(context.Context, <-chan InputType) <-chan OutputType) <-chan OutputType {
+	opOut := op(p.ctx, in)
+	out := make(chan OutputType)
+	p.Go(func(ctx context.Context) {
+		defer close(out)
+		sending := true
+		for v := range opOut {
+			sending = sending && trySend(ctx, out, v)
+		}
+	})
+	return out
+}
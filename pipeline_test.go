@@ -0,0 +1,87 @@
+package channels
+
+import (
+	"context"
+	"reflect"
+	"runtime"
+	"testing"
+	"time"
+)
+
+func TestPipeline(t *testing.T) {
+	t.Parallel()
+	p := NewPipeline(context.Background())
+	source := make(chan int)
+	p.Go(func(ctx context.Context) {
+		defer close(source)
+		for i := 1; i <= 5; i++ {
+			if !Send(ctx, source, i) {
+				return
+			}
+		}
+	})
+	doubled := Stage(p, source, func(ctx context.Context, in <-chan int) <-chan int {
+		return Map(ctx, in, func(v int) int { return v * 2 })
+	})
+
+	values := ToSlice(context.TODO(), doubled)
+	expected := []int{2, 4, 6, 8, 10}
+	if !reflect.DeepEqual(values, expected) {
+		t.Errorf("wrong values returned\nwant %#v\ngot  %#v", expected, values)
+	}
+	if err := p.Shutdown(context.TODO()); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+// TestPipelineShutdown doesn't run in parallel, so other tests don't affect
+// the number of goroutines.
+func TestPipelineShutdown(t *testing.T) {
+	before := runtime.NumGoroutine()
+
+	p := NewPipeline(context.Background())
+	source := make(chan int)
+	p.Go(func(ctx context.Context) {
+		defer close(source)
+		for i := 0; Send(ctx, source, i); i++ {
+		}
+	})
+	evens := Stage(p, source, func(ctx context.Context, in <-chan int) <-chan int {
+		return Filter(ctx, in, func(v int) bool { return v%2 == 0 })
+	})
+	batches := Stage(p, evens, func(ctx context.Context, in <-chan int) <-chan []int {
+		return Chunk(ctx, in, 3)
+	})
+	<-batches
+
+	if err := p.Shutdown(context.TODO()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, ok := <-batches; ok {
+		t.Error("unexpected open output channel after Shutdown")
+	}
+
+	deadline := time.Now().Add(time.Second)
+	for runtime.NumGoroutine() > before && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	if after := runtime.NumGoroutine(); after > before {
+		t.Errorf("goroutines leaked after Shutdown\nwant %d\ngot  %d", before, after)
+	}
+}
+
+func TestPipelineShutdownTimeout(t *testing.T) {
+	t.Parallel()
+	p := NewPipeline(context.Background())
+	release := make(chan struct{})
+	defer close(release)
+	p.Go(func(context.Context) {
+		<-release
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	if err := p.Shutdown(ctx); err != context.DeadlineExceeded {
+		t.Errorf("wrong error returned\nwant %#v\ngot  %#v", context.DeadlineExceeded, err)
+	}
+}
@@ -0,0 +1,133 @@
+package channels
+
+import (
+	"context"
+	"reflect"
+	"sync"
+)
+
+// Recorder records the values sent to its channel. It's meant to be used in
+// tests, as a sink that can be inspected after the pipeline runs.
+//
+// A Recorder is safe for concurrent use by multiple goroutines.
+type Recorder[T any] struct {
+	ch   chan T
+	done chan struct{}
+
+	mu     sync.Mutex
+	values []T
+}
+
+// NewRecorder creates a Recorder and launches a goroutine that records the
+// values sent to its channel, until the channel is closed or the provided
+// context is cancelled.
+func NewRecorder[T any](ctx context.Context) *Recorder[T] {
+	r := &Recorder[T]{ch: make(chan T), done: make(chan struct{})}
+	go func() {
+		defer close(r.done)
+		receiveLoop(ctx, r.ch, func(v T) bool {
+			r.mu.Lock()
+			defer r.mu.Unlock()
+			r.values = append(r.values, v)
+			return true
+		})
+	}()
+	return r
+}
+
+// Channel returns the channel where values should be sent for recording. The
+// channel should be closed once all values are sent.
+func (r *Recorder[T]) Channel() chan T {
+	return r.ch
+}
+
+// Recorded returns a copy of the values recorded so far.
+func (r *Recorder[T]) Recorded() []T {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.values == nil {
+		return nil
+	}
+	return append([]T(nil), r.values...)
+}
+
+// Wait blocks until the channel of the Recorder is closed or the context
+// provided to NewRecorder is cancelled, and returns the recorded values.
+func (r *Recorder[T]) Wait() []T {
+	<-r.done
+	return r.Recorded()
+}
+
+// Matches waits for the recording to finish, as described in Wait, and
+// reports whether the recorded values are deeply equal to the expected ones.
+func (r *Recorder[T]) Matches(expected []T) bool {
+	return reflect.DeepEqual(r.Wait(), expected)
+}
+
+// Replay returns a channel that emits the provided values, in order.
+//
+// The output channel is unbuffered.
+//
+// This is a non-blocking function: it launches a goroutine and returns the
+// channel for consumption. In order to stop the inner goroutine, one can
+// cancel the provided context.
+//
+// The output channel is always closed on cancellation or after all values
+// are sent.
+func Replay[T any](ctx context.Context, values []T) <-chan T {
+	out := make(chan T)
+	go func() {
+		defer close(out)
+		for _, v := range values {
+			if !trySend(ctx, out, v) {
+				return
+			}
+		}
+	}()
+	return out
+}
+
+// Cache drains the input channel into memory and returns a function that
+// replays the captured values, along with a channel that is closed once the
+// capture is complete. Each call to the replay function returns a fresh
+// channel that emits all the captured values, in order, so an expensive
+// upstream can be consumed multiple times while running only once. Channels
+// returned before the capture is complete wait for it before emitting any
+// values.
+//
+// If the provided context is cancelled before the input channel is closed,
+// the capture is complete with the values received so far.
+//
+// The channels returned by the replay function are unbuffered, and are always
+// closed on cancellation of the context provided to the replay function or
+// after all values are sent.
+//
+// This is a non-blocking function: it launches a goroutine that captures the
+// values. In order to stop it, one can close the input channel or cancel the
+// provided context.
+func Cache[T any](ctx context.Context, in <-chan T) (replay func(context.Context) <-chan T, done <-chan struct{}) {
+	captured := make(chan struct{})
+	var values []T
+	go func() {
+		defer close(captured)
+		values = ToSlice(ctx, in)
+	}()
+	replay = func(ctx context.Context) <-chan T {
+		out := make(chan T)
+		go func() {
+			defer close(out)
+			select {
+			case <-captured:
+			case <-ctx.Done():
+				return
+			}
+			for _, v := range values {
+				if !trySend(ctx, out, v) {
+					return
+				}
+			}
+		}()
+		return out
+	}
+	return replay, captured
+}
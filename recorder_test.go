@@ -0,0 +1,137 @@
+package channels
+
+import (
+	"context"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestRecorder(t *testing.T) {
+	t.Parallel()
+	ch := startGenerator(t, 0, func(p int) (int, bool) {
+		if p > 3 {
+			return p, false
+		}
+		return p + 1, true
+	}, nil)
+
+	r := NewRecorder[int](context.TODO())
+	Consume(context.TODO(), Map(context.TODO(), ch, func(v int) int { return v * 3 }), func(v int) bool {
+		r.Channel() <- v
+		return true
+	})
+	close(r.Channel())
+
+	expected := []int{3, 6, 9, 12}
+	if !r.Matches(expected) {
+		t.Errorf("wrong values recorded\nwant %#v\ngot  %#v", expected, r.Recorded())
+	}
+}
+
+func TestRecorderConcurrentSenders(t *testing.T) {
+	t.Parallel()
+	r := NewRecorder[int](context.TODO())
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 10; j++ {
+				r.Channel() <- j
+				r.Recorded()
+			}
+		}()
+	}
+	wg.Wait()
+	close(r.Channel())
+
+	if values := r.Wait(); len(values) != 40 {
+		t.Errorf("wrong number of values recorded\nwant 40\ngot  %d", len(values))
+	}
+}
+
+func TestRecorderWithContextCancellation(t *testing.T) {
+	t.Parallel()
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	r := NewRecorder[string](ctx)
+	if values := r.Wait(); values != nil {
+		t.Errorf("unexpected non-nil slice: %#v", values)
+	}
+}
+
+func TestReplay(t *testing.T) {
+	t.Parallel()
+	values := ToSlice(context.TODO(), Replay(context.TODO(), []string{"a", "b", "c"}))
+	expectedSlice := []string{"a", "b", "c"}
+	if !reflect.DeepEqual(values, expectedSlice) {
+		t.Errorf("wrong values returned\nwant %#v\ngot  %#v", expectedSlice, values)
+	}
+}
+
+func TestReplayWithContextCancellation(t *testing.T) {
+	t.Parallel()
+	ctx, cancel := context.WithCancel(context.Background())
+	ch := Replay(ctx, []int{1, 2, 3})
+	<-ch
+	cancel()
+	time.Sleep(10 * time.Millisecond)
+	if values := ToSlice(context.TODO(), ch); len(values) > 1 {
+		t.Errorf("unexpected values after cancellation: %#v", values)
+	}
+}
+
+func TestCache(t *testing.T) {
+	t.Parallel()
+	in := make(chan int)
+	replay, done := Cache(context.TODO(), in)
+
+	// a replay requested before the capture is complete waits for it.
+	early := replay(context.TODO())
+	in <- 1
+	in <- 2
+	select {
+	case v := <-early:
+		t.Fatalf("unexpected value before the capture is complete: %d", v)
+	case <-done:
+		t.Fatal("unexpected capture completion before the input channel is closed")
+	case <-time.After(50 * time.Millisecond):
+	}
+	in <- 3
+	close(in)
+	<-done
+
+	expected := []int{1, 2, 3}
+	for i, ch := range []<-chan int{early, replay(context.TODO()), replay(context.TODO())} {
+		if values := ToSlice(context.TODO(), ch); !reflect.DeepEqual(values, expected) {
+			t.Errorf("wrong values returned by replay %d\nwant %#v\ngot  %#v", i, expected, values)
+		}
+	}
+}
+
+func TestCacheWithContextCancellation(t *testing.T) {
+	t.Parallel()
+	ch := startGenerator(t, 0, func(p int) (int, bool) {
+		return p + 1, true
+	}, func() { time.Sleep(10 * time.Millisecond) })
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	replay, done := Cache(ctx, ch)
+	<-done
+	values := ToSlice(context.TODO(), replay(context.TODO()))
+	if len(values) == 0 {
+		t.Fatal("unexpected empty slice")
+	}
+
+	replayCtx, replayCancel := context.WithCancel(context.Background())
+	replayed := replay(replayCtx)
+	<-replayed
+	replayCancel()
+	time.Sleep(10 * time.Millisecond)
+	if values := ToSlice(context.TODO(), replayed); len(values) > 1 {
+		t.Errorf("unexpected values after cancellation: %#v", values)
+	}
+}
@@ -0,0 +1,215 @@
+package channels
+
+import (
+	"container/heap"
+	"context"
+	"sync"
+	"time"
+)
+
+// GroupByReduce groups the values from the input channel by the key returned
+// by the provided key function, and reduces the values of each group with the
+// provided function, starting from the value returned by initial. It returns
+// a map from each key to the reduction of the values with that key.
+//
+// This is a blocking function that can be aborted via the provided context or
+// by closing the input channel. On cancellation, it returns the reductions of
+// the values received so far.
+func GroupByReduce[T any, K comparable, A any](ctx context.Context, in <-chan T, key func(T) K, initial func() A, f func(A, T) A) map[K]A {
+	result := make(map[K]A)
+	receiveLoop(ctx, in, func(v T) bool {
+		k := key(v)
+		acc, ok := result[k]
+		if !ok {
+			acc = initial()
+		}
+		result[k] = f(acc, v)
+		return true
+	})
+	return result
+}
+
+// IndexBy drains the input channel into a map from the key returned by the
+// key function to the value, and returns a function to look up values by key,
+// along with a channel that is closed once the input channel is closed or the
+// provided context is cancelled. When multiple values have the same key, the
+// last one received wins.
+//
+// The lookup function is safe for concurrent use, and can be called before
+// the ready channel is closed, in which case it only sees the values indexed
+// so far.
+//
+// This is a non-blocking function: it launches a goroutine that builds the
+// index. In order to stop it, one can close the input channel or cancel the
+// provided context.
+func IndexBy[T any, K comparable](ctx context.Context, in <-chan T, key func(T) K) (lookup func(K) (T, bool), ready <-chan struct{}) {
+	var mu sync.RWMutex
+	index := make(map[K]T)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		receiveLoop(ctx, in, func(v T) bool {
+			k := key(v)
+			mu.Lock()
+			defer mu.Unlock()
+			index[k] = v
+			return true
+		})
+	}()
+	lookup = func(k K) (T, bool) {
+		mu.RLock()
+		defer mu.RUnlock()
+		v, ok := index[k]
+		return v, ok
+	}
+	return lookup, done
+}
+
+// TopK returns the k largest values from the input channel, according to the
+// provided less function, sorted from the largest to the smallest. Between
+// values that are equal, the ones received first are preferred.
+//
+// Only k values are kept in memory at any given time. If the input channel
+// has fewer than k values, all of them are returned.
+//
+// This is a blocking function that can be aborted via the provided context or
+// by closing the input channel. On cancellation, it returns the k largest
+// values received so far.
+func TopK[T any](ctx context.Context, in <-chan T, k int, less func(a, b T) bool) []T {
+	if k < 1 {
+		return nil
+	}
+	h := &minHeap[T]{less: less}
+	receiveLoop(ctx, in, func(v T) bool {
+		if h.Len() < k {
+			heap.Push(h, v)
+		} else if less(h.values[0], v) {
+			h.values[0] = v
+			heap.Fix(h, 0)
+		}
+		return true
+	})
+	result := make([]T, h.Len())
+	for i := len(result) - 1; i >= 0; i-- {
+		result[i] = heap.Pop(h).(T)
+	}
+	return result
+}
+
+// minHeap is a heap ordered by the less function, so the smallest value is
+// always at the root. In TopK, that's the smallest of the top values, ready to
+// be replaced.
+type minHeap[T any] struct {
+	values []T
+	less   func(a, b T) bool
+}
+
+func (h *minHeap[T]) Len() int {
+	return len(h.values)
+}
+
+func (h *minHeap[T]) Less(i, j int) bool {
+	return h.less(h.values[i], h.values[j])
+}
+
+func (h *minHeap[T]) Swap(i, j int) {
+	h.values[i], h.values[j] = h.values[j], h.values[i]
+}
+
+func (h *minHeap[T]) Push(x any) {
+	h.values = append(h.values, x.(T))
+}
+
+func (h *minHeap[T]) Pop() any {
+	last := len(h.values) - 1
+	v := h.values[last]
+	h.values = h.values[:last]
+	return v
+}
+
+// DrainInfo consumes the input channel and returns the number of values
+// received and the last of them. The boolean return value is false if no
+// values were received.
+//
+// This is a blocking function that can be aborted via the provided context or
+// by closing the input channel. On cancellation, it returns the count and the
+// last value received so far.
+func DrainInfo[T any](ctx context.Context, in <-chan T) (count int, last T, ok bool) {
+	receiveLoop(ctx, in, func(v T) bool {
+		count++
+		last = v
+		return true
+	})
+	return count, last, count > 0
+}
+
+// MeasureThroughput consumes the input channel and returns the number of
+// values received, the time elapsed between the call and the end of the
+// stream, and the resulting rate in values per second. The rate is 0 if no
+// time elapsed.
+//
+// See WithClock for changing the clock used to read the current time.
+//
+// This is a blocking function that can be aborted via the provided context or
+// by closing the input channel. On cancellation, it returns the measurement
+// for the values received so far.
+func MeasureThroughput[T any](ctx context.Context, in <-chan T, opts ...TimeOption) (count int, elapsed time.Duration, perSec float64) {
+	options := newTimeOptions(opts)
+	start := options.clock.Now()
+	count, _, _ = DrainInfo(ctx, in)
+	elapsed = options.clock.Now().Sub(start)
+	if elapsed > 0 {
+		perSec = float64(count) / elapsed.Seconds()
+	}
+	return count, elapsed, perSec
+}
+
+// ReduceInto calls f with the provided accumulator and each value from the
+// input channel, so f can update the accumulator in place. The accumulator
+// should be a reference type, such as a pointer or a map, otherwise the
+// updates made by f are lost.
+//
+// This is a blocking function that can be aborted via the provided context or
+// by closing the input channel. On cancellation, the accumulator holds the
+// updates for the values received so far.
+func ReduceInto[T, A any](ctx context.Context, in <-chan T, acc A, f func(A, T)) {
+	receiveLoop(ctx, in, func(v T) bool {
+		f(acc, v)
+		return true
+	})
+}
+
+// ReduceCount folds the values from the input channel into an accumulator,
+// starting with initial, and returns the final accumulator along with the
+// number of values folded. This makes it possible to compute aggregations such
+// as averages in a single pass.
+//
+// This is a blocking function that can be aborted via the provided context or
+// by closing the input channel. On cancellation, it returns the accumulator
+// and the count for the values received so far.
+func ReduceCount[T, A any](ctx context.Context, in <-chan T, initial A, f func(A, T) A) (A, int) {
+	acc, count := initial, 0
+	receiveLoop(ctx, in, func(v T) bool {
+		acc = f(acc, v)
+		count++
+		return true
+	})
+	return acc, count
+}
+
+// Summarize collects all values from the input channel, like ToSlice, and
+// then calls each of the provided reducers with the collected values. It
+// returns the collected values and the results of the reducers, in the order
+// the reducers are provided.
+//
+// This is a blocking function that can be aborted via the provided context or
+// by closing the input channel. On cancellation, the reducers are called with
+// the values received so far.
+func Summarize[T, R any](ctx context.Context, in <-chan T, reducers ...func([]T) R) ([]T, []R) {
+	values := ToSlice(ctx, in)
+	summaries := make([]R, len(reducers))
+	for i, reduce := range reducers {
+		summaries[i] = reduce(values)
+	}
+	return values, summaries
+}
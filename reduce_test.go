@@ -0,0 +1,381 @@
+package channels
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestGroupByReduce(t *testing.T) {
+	t.Parallel()
+	ch := startGenerator(t, 0, func(p int) (int, bool) {
+		if p > 9 {
+			return p, false
+		}
+		return p + 1, true
+	}, nil)
+
+	sums := GroupByReduce(context.TODO(), ch, func(v int) int { return v % 3 }, func() int { return 0 }, func(acc, v int) int {
+		return acc + v
+	})
+	expected := map[int]int{0: 18, 1: 22, 2: 15}
+	if !reflect.DeepEqual(sums, expected) {
+		t.Errorf("wrong sums returned\nwant %#v\ngot  %#v", expected, sums)
+	}
+}
+
+func TestGroupByReduceInitialPerKey(t *testing.T) {
+	t.Parallel()
+	ch := startGenerator(t, 0, func(p int) (int, bool) {
+		if p > 5 {
+			return p, false
+		}
+		return p + 1, true
+	}, nil)
+
+	groups := GroupByReduce(context.TODO(), ch, func(v int) bool { return v%2 == 0 }, func() []int { return []int{} }, func(acc []int, v int) []int {
+		return append(acc, v)
+	})
+	expected := map[bool][]int{false: {1, 3, 5}, true: {2, 4, 6}}
+	if !reflect.DeepEqual(groups, expected) {
+		t.Errorf("wrong groups returned\nwant %#v\ngot  %#v", expected, groups)
+	}
+}
+
+func TestGroupByReduceWithContextCancellation(t *testing.T) {
+	t.Parallel()
+	ch := startGenerator(t, 0, func(p int) (int, bool) {
+		return p + 1, true
+	}, func() { time.Sleep(10 * time.Millisecond) })
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	counts := GroupByReduce(ctx, ch, func(v int) int { return v % 2 }, func() int { return 0 }, func(acc, _ int) int {
+		return acc + 1
+	})
+	if len(counts) == 0 {
+		t.Fatal("unexpected empty map")
+	}
+}
+
+func TestIndexBy(t *testing.T) {
+	t.Parallel()
+	records := []jsonRecord{
+		{Name: "a", Count: 1},
+		{Name: "b", Count: 2},
+		{Name: "a", Count: 3},
+	}
+	lookup, ready := IndexBy(context.TODO(), fromSlice(t, records), func(r jsonRecord) string { return r.Name })
+	<-ready
+
+	var tests = []struct {
+		key        string
+		expected   jsonRecord
+		expectedOK bool
+	}{
+		{"a", jsonRecord{Name: "a", Count: 3}, true},
+		{"b", jsonRecord{Name: "b", Count: 2}, true},
+		{"c", jsonRecord{}, false},
+	}
+	for _, test := range tests {
+		if got, ok := lookup(test.key); got != test.expected || ok != test.expectedOK {
+			t.Errorf("wrong lookup result for %q\nwant %#v, %t\ngot  %#v, %t", test.key, test.expected, test.expectedOK, got, ok)
+		}
+	}
+}
+
+func TestIndexByWithContextCancellation(t *testing.T) {
+	t.Parallel()
+	ch := startGenerator(t, 0, func(p int) (int, bool) {
+		return p + 1, true
+	}, func() { time.Sleep(10 * time.Millisecond) })
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	lookup, ready := IndexBy(ctx, ch, func(v int) int { return v })
+	<-ready
+	if v, ok := lookup(1); !ok || v != 1 {
+		t.Errorf("wrong lookup result\nwant 1, true\ngot  %d, %t", v, ok)
+	}
+}
+
+func TestTopK(t *testing.T) {
+	t.Parallel()
+	in := make(chan int, 10)
+	for _, v := range []int{5, 1, 9, 3, 7, 9, 2, 8, 6, 4} {
+		in <- v
+	}
+	close(in)
+
+	values := TopK(context.TODO(), in, 4, func(a, b int) bool { return a < b })
+	expectedSlice := []int{9, 9, 8, 7}
+	if !reflect.DeepEqual(values, expectedSlice) {
+		t.Errorf("wrong values returned\nwant %#v\ngot  %#v", expectedSlice, values)
+	}
+}
+
+func TestTopKTies(t *testing.T) {
+	t.Parallel()
+	type score struct {
+		name   string
+		points int
+	}
+	in := make(chan score, 5)
+	for _, s := range []score{{"a", 10}, {"b", 20}, {"c", 10}, {"d", 5}, {"e", 10}} {
+		in <- s
+	}
+	close(in)
+
+	values := TopK(context.TODO(), in, 3, func(a, b score) bool { return a.points < b.points })
+	if len(values) != 3 {
+		t.Fatalf("wrong number of values returned\nwant 3\ngot  %d", len(values))
+	}
+	if values[0] != (score{"b", 20}) {
+		t.Errorf("wrong first value\nwant %#v\ngot  %#v", score{"b", 20}, values[0])
+	}
+	for _, s := range values[1:] {
+		if s.points != 10 {
+			t.Errorf("wrong value returned: %#v", s)
+		}
+		if s.name == "e" {
+			t.Errorf("unexpected late tie returned: %#v", s)
+		}
+	}
+}
+
+func TestTopKShorterThanK(t *testing.T) {
+	t.Parallel()
+	ch := startGenerator(t, 0, func(p int) (int, bool) {
+		if p > 2 {
+			return p, false
+		}
+		return p + 1, true
+	}, nil)
+
+	values := TopK(context.TODO(), ch, 5, func(a, b int) bool { return a < b })
+	expectedSlice := []int{3, 2, 1}
+	if !reflect.DeepEqual(values, expectedSlice) {
+		t.Errorf("wrong values returned\nwant %#v\ngot  %#v", expectedSlice, values)
+	}
+}
+
+func TestTopKZero(t *testing.T) {
+	t.Parallel()
+	ch := startGenerator(t, 0, func(p int) (int, bool) {
+		if p > 2 {
+			return p, false
+		}
+		return p + 1, true
+	}, nil)
+
+	values := TopK(context.TODO(), ch, 0, func(a, b int) bool { return a < b })
+	if values != nil {
+		t.Errorf("unexpected non-nil slice: %#v", values)
+	}
+}
+
+func TestTopKWithContextCancellation(t *testing.T) {
+	t.Parallel()
+	ch := startGenerator(t, 0, func(p int) (int, bool) {
+		return p + 1, true
+	}, func() { time.Sleep(10 * time.Millisecond) })
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	values := TopK(ctx, ch, 3, func(a, b int) bool { return a < b })
+	if len(values) == 0 {
+		t.Fatal("unexpected empty slice")
+	}
+}
+
+func TestDrainInfo(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name          string
+		values        []string
+		expectedCount int
+		expectedLast  string
+		expectedOK    bool
+	}{
+		{name: "empty stream"},
+		{name: "single element", values: []string{"a"}, expectedCount: 1, expectedLast: "a", expectedOK: true},
+		{name: "multiple elements", values: []string{"a", "b", "c"}, expectedCount: 3, expectedLast: "c", expectedOK: true},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+			count, last, ok := DrainInfo(context.TODO(), fromSlice(t, test.values))
+			if count != test.expectedCount || last != test.expectedLast || ok != test.expectedOK {
+				t.Errorf("wrong result\nwant %d, %q, %t\ngot  %d, %q, %t", test.expectedCount, test.expectedLast, test.expectedOK, count, last, ok)
+			}
+		})
+	}
+}
+
+func TestDrainInfoWithContextCancellation(t *testing.T) {
+	t.Parallel()
+	ch := startGenerator(t, 0, func(p int) (int, bool) {
+		return p + 1, true
+	}, func() { time.Sleep(10 * time.Millisecond) })
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	count, last, ok := DrainInfo(ctx, ch)
+	if !ok || count == 0 {
+		t.Fatalf("unexpected empty stream: %d, %d, %t", count, last, ok)
+	}
+	if last != count {
+		t.Errorf("wrong last value\nwant %d\ngot  %d", count, last)
+	}
+}
+
+func TestMeasureThroughput(t *testing.T) {
+	t.Parallel()
+	ch := fromSlice(t, []int{1, 2, 3, 4, 5, 6})
+	now, _ := fakeClock(2 * time.Second)
+
+	count, elapsed, perSec := MeasureThroughput(context.TODO(), ch, WithClock(testClock{now: now}))
+	if count != 6 || elapsed != 2*time.Second || perSec != 3 {
+		t.Errorf("wrong result\nwant %d, %s, %v\ngot  %d, %s, %v", 6, 2*time.Second, 3.0, count, elapsed, perSec)
+	}
+}
+
+func TestMeasureThroughputWithContextCancellation(t *testing.T) {
+	t.Parallel()
+	ch := startGenerator(t, 0, func(p int) (int, bool) {
+		return p + 1, true
+	}, func() { time.Sleep(10 * time.Millisecond) })
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	count, elapsed, perSec := MeasureThroughput(ctx, ch)
+	if count == 0 || elapsed < 100*time.Millisecond || perSec <= 0 {
+		t.Errorf("unexpected partial measurement: %d, %s, %v", count, elapsed, perSec)
+	}
+}
+
+func TestReduceInto(t *testing.T) {
+	t.Parallel()
+	in := fromSlice(t, []string{"go", "rust", "go", "zig", "go", "rust"})
+	counts := map[string]int{}
+	ReduceInto(context.TODO(), in, counts, func(acc map[string]int, v string) {
+		acc[v]++
+	})
+
+	expected := map[string]int{"go": 3, "rust": 2, "zig": 1}
+	if !reflect.DeepEqual(counts, expected) {
+		t.Errorf("wrong counts\nwant %#v\ngot  %#v", expected, counts)
+	}
+}
+
+func TestReduceIntoWithContextCancellation(t *testing.T) {
+	t.Parallel()
+	ch := startGenerator(t, 0, func(p int) (int, bool) {
+		return p + 1, true
+	}, func() { time.Sleep(10 * time.Millisecond) })
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	var sum int
+	ReduceInto(ctx, ch, &sum, func(acc *int, v int) {
+		*acc += v
+	})
+	if sum == 0 {
+		t.Fatal("unexpected zero sum")
+	}
+}
+
+func TestReduceCount(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name          string
+		values        []int
+		expectedSum   int
+		expectedCount int
+	}{
+		{name: "empty stream"},
+		{name: "single element", values: []int{4}, expectedSum: 4, expectedCount: 1},
+		{name: "multiple elements", values: []int{1, 2, 3, 4}, expectedSum: 10, expectedCount: 4},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+			sum, count := ReduceCount(context.TODO(), fromSlice(t, test.values), 0, func(acc, v int) int {
+				return acc + v
+			})
+			if sum != test.expectedSum || count != test.expectedCount {
+				t.Errorf("wrong result\nwant %d, %d\ngot  %d, %d", test.expectedSum, test.expectedCount, sum, count)
+			}
+		})
+	}
+}
+
+func TestReduceCountWithContextCancellation(t *testing.T) {
+	t.Parallel()
+	ch := startGenerator(t, 0, func(p int) (int, bool) {
+		return p + 1, true
+	}, func() { time.Sleep(10 * time.Millisecond) })
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	sum, count := ReduceCount(ctx, ch, 0, func(acc, v int) int {
+		return acc + v
+	})
+	if count == 0 {
+		t.Fatal("unexpected empty stream")
+	}
+	if expected := count * (count + 1) / 2; sum != expected {
+		t.Errorf("wrong partial sum\nwant %d\ngot  %d", expected, sum)
+	}
+}
+
+func TestSummarize(t *testing.T) {
+	t.Parallel()
+	ch := startGenerator(t, 0, func(p int) (int, bool) {
+		if p > 4 {
+			return p, false
+		}
+		return p + 1, true
+	}, nil)
+
+	count := func(values []int) int { return len(values) }
+	sum := func(values []int) int {
+		total := 0
+		for _, v := range values {
+			total += v
+		}
+		return total
+	}
+	values, summaries := Summarize(context.TODO(), ch, count, sum)
+
+	expectedValues := []int{1, 2, 3, 4, 5}
+	if !reflect.DeepEqual(values, expectedValues) {
+		t.Errorf("wrong values returned\nwant %#v\ngot  %#v", expectedValues, values)
+	}
+	expectedSummaries := []int{5, 15}
+	if !reflect.DeepEqual(summaries, expectedSummaries) {
+		t.Errorf("wrong summaries returned\nwant %#v\ngot  %#v", expectedSummaries, summaries)
+	}
+}
+
+func TestSummarizeWithContextCancellation(t *testing.T) {
+	t.Parallel()
+	ch := startGenerator(t, 0, func(p int) (int, bool) {
+		return p + 1, true
+	}, func() { time.Sleep(10 * time.Millisecond) })
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	values, summaries := Summarize(ctx, ch, func(values []int) int { return len(values) })
+	if len(values) == 0 {
+		t.Fatal("unexpected empty slice")
+	}
+	if expected := []int{len(values)}; !reflect.DeepEqual(summaries, expected) {
+		t.Errorf("wrong summaries returned\nwant %#v\ngot  %#v", expected, summaries)
+	}
+}
@@ -0,0 +1,124 @@
+package channels
+
+import (
+	"container/heap"
+	"context"
+	"time"
+)
+
+// Reorder takes an input channel of approximately ordered values and returns
+// an output channel that emits them sorted according to the less function,
+// which should be consistent with the timestamps returned by the ts function.
+//
+// Values are buffered until they're older than the watermark, which trails
+// the largest timestamp received so far by lateness: a value is emitted once
+// its timestamp isn't after the watermark. Values whose timestamp is before
+// the watermark when they're received arrive too late to be emitted in order
+// and are discarded. Since the watermark only advances with the timestamps of
+// the values received, no wall clock is involved.
+//
+// When the input channel is closed, all the buffered values are emitted in
+// order before the output channel is closed.
+//
+// The capacity of the output channel will be same as the capacity of the input
+// channel.
+//
+// This is a non-blocking function: it launches a goroutine and returns the
+// channel for consumption. In order to stop the inner goroutine, one can close
+// the input channel or cancel the provided context.
+//
+// The output channel is always closed on cancellation, even if the input
+// channel is never closed. Buffered values are discarded on cancellation.
+func Reorder[T any](ctx context.Context, in <-chan T, less func(a, b T) bool, lateness time.Duration, ts func(T) time.Time) <-chan T {
+	out := make(chan T, cap(in))
+	go func() {
+		defer close(out)
+		h := &minHeap[T]{less: less}
+		var (
+			watermark time.Time
+			started   bool
+		)
+		emit := func(ready func(T) bool) bool {
+			for h.Len() > 0 && ready(h.values[0]) {
+				if !trySend(ctx, out, heap.Pop(h).(T)) {
+					return false
+				}
+			}
+			return true
+		}
+		ok := true
+		receiveLoop(ctx, in, func(v T) bool {
+			t := ts(v)
+			if started && t.Before(watermark) {
+				return true
+			}
+			if w := t.Add(-lateness); !started || w.After(watermark) {
+				watermark = w
+				started = true
+			}
+			heap.Push(h, v)
+			ok = emit(func(v T) bool {
+				return !ts(v).After(watermark)
+			})
+			return ok
+		})
+		if ok && ctx.Err() == nil {
+			emit(func(T) bool { return true })
+		}
+	}()
+	return out
+}
+
+// SortedBuffer takes an input channel and returns an output channel that emits
+// the values from the input channel sorted according to the less function,
+// without waiting for the input channel to be closed. Values are kept in a
+// sorted buffer, and whenever the buffer holds 2*emitEvery values, the
+// smallest emitEvery values are emitted, in order. The remaining values are
+// emitted, in order, when the input channel is closed. If emitEvery is lower
+// than 1, it's treated as 1.
+//
+// Each batch of values is sorted, and each emitted value is smaller than or
+// equal to the values buffered at the time, but a value received after a
+// flush may be smaller than values already emitted. So the output is only
+// guaranteed to be globally sorted if no flush happens before the input
+// channel is closed.
+//
+// The capacity of the output channel will be same as the capacity of the input
+// channel.
+//
+// This is a non-blocking function: it launches a goroutine and returns the
+// channel for consumption. In order to stop the inner goroutine, one can close
+// the input channel or cancel the provided context.
+//
+// The output channel is always closed on cancellation, even if the input
+// channel is never closed. Buffered values are discarded on cancellation.
+func SortedBuffer[T any](ctx context.Context, in <-chan T, less func(a, b T) bool, emitEvery int) <-chan T {
+	if emitEvery < 1 {
+		emitEvery = 1
+	}
+	out := make(chan T, cap(in))
+	go func() {
+		defer close(out)
+		h := &minHeap[T]{less: less}
+		emit := func(n int) bool {
+			for ; n > 0 && h.Len() > 0; n-- {
+				if !trySend(ctx, out, heap.Pop(h).(T)) {
+					return false
+				}
+			}
+			return true
+		}
+		ok := true
+		receiveLoop(ctx, in, func(v T) bool {
+			heap.Push(h, v)
+			if h.Len() >= 2*emitEvery {
+				ok = emit(emitEvery)
+			}
+			return ok
+		})
+		if ok && ctx.Err() == nil {
+			emit(h.Len())
+		}
+	}()
+	return out
+}
@@ -0,0 +1,133 @@
+package channels
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestReorder(t *testing.T) {
+	t.Parallel()
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	ch := fromSlice(t, []int{2, 1, 3, 5, 4, 7, 6, 1, 9, 8})
+
+	ts := func(v int) time.Time { return start.Add(time.Duration(v) * time.Second) }
+	less := func(a, b int) bool { return a < b }
+	values := ToSlice(context.TODO(), Reorder(context.TODO(), ch, less, 2*time.Second, ts))
+
+	// 1 is received again after the watermark moved past it, so it's
+	// discarded.
+	expected := []int{1, 2, 3, 4, 5, 6, 7, 8, 9}
+	if !reflect.DeepEqual(values, expected) {
+		t.Errorf("wrong values returned\nwant %#v\ngot  %#v", expected, values)
+	}
+}
+
+func TestReorderEmitsBeforeClose(t *testing.T) {
+	t.Parallel()
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	in := make(chan int)
+	defer close(in)
+
+	ts := func(v int) time.Time { return start.Add(time.Duration(v) * time.Second) }
+	less := func(a, b int) bool { return a < b }
+	out := Reorder(context.TODO(), in, less, time.Second, ts)
+
+	// with a lateness of one second, each value sent after the first moves
+	// the watermark enough to emit the smallest buffered value.
+	in <- 2
+	var steps = []struct {
+		send     int
+		expected int
+	}{
+		{1, 1},
+		{3, 2},
+	}
+	for _, step := range steps {
+		in <- step.send
+		if got := <-out; got != step.expected {
+			t.Errorf("wrong value returned\nwant %d\ngot  %d", step.expected, got)
+		}
+	}
+	select {
+	case v := <-out:
+		t.Errorf("unexpected value emitted before the watermark: %d", v)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func TestReorderWithContextCancellation(t *testing.T) {
+	t.Parallel()
+	ch := startGenerator(t, 0, func(p int) (int, bool) {
+		return p + 1, true
+	}, func() { time.Sleep(time.Second) })
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	ts := func(v int) time.Time { return time.Unix(int64(v), 0) }
+	values := ToSlice(context.TODO(), Reorder(ctx, ch, func(a, b int) bool { return a < b }, time.Second, ts))
+	if values != nil {
+		t.Errorf("unexpected non-nil slice: %#v", values)
+	}
+}
+
+func TestSortedBuffer(t *testing.T) {
+	t.Parallel()
+	less := func(a, b int) bool { return a < b }
+
+	t.Run("sorts the whole stream without flushes", func(t *testing.T) {
+		t.Parallel()
+		ch := fromSlice(t, []int{5, 3, 1, 4, 2})
+		values := ToSlice(context.TODO(), SortedBuffer(context.TODO(), ch, less, 3))
+		expected := []int{1, 2, 3, 4, 5}
+		if !reflect.DeepEqual(values, expected) {
+			t.Errorf("wrong values returned\nwant %#v\ngot  %#v", expected, values)
+		}
+	})
+
+	t.Run("flushes the smallest values", func(t *testing.T) {
+		t.Parallel()
+		in := make(chan int)
+		defer close(in)
+		out := SortedBuffer(context.TODO(), in, less, 2)
+
+		for _, v := range []int{8, 6, 7, 5} {
+			in <- v
+		}
+		for _, expected := range []int{5, 6} {
+			if got := <-out; got != expected {
+				t.Errorf("wrong value returned\nwant %d\ngot  %d", expected, got)
+			}
+		}
+		select {
+		case v := <-out:
+			t.Errorf("unexpected value emitted before the buffer is full: %d", v)
+		case <-time.After(50 * time.Millisecond):
+		}
+	})
+
+	t.Run("values smaller than flushed values", func(t *testing.T) {
+		t.Parallel()
+		ch := fromSlice(t, []int{4, 3, 2, 1, 0, 9})
+		values := ToSlice(context.TODO(), SortedBuffer(context.TODO(), ch, less, 1))
+		expected := []int{3, 2, 1, 0, 4, 9}
+		if !reflect.DeepEqual(values, expected) {
+			t.Errorf("wrong values returned\nwant %#v\ngot  %#v", expected, values)
+		}
+	})
+}
+
+func TestSortedBufferWithContextCancellation(t *testing.T) {
+	t.Parallel()
+	ch := startGenerator(t, 0, func(p int) (int, bool) {
+		return p + 1, true
+	}, func() { time.Sleep(time.Second) })
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	values := ToSlice(context.TODO(), SortedBuffer(ctx, ch, func(a, b int) bool { return a < b }, 1))
+	if values != nil {
+		t.Errorf("unexpected non-nil slice: %#v", values)
+	}
+}
@@ -0,0 +1,140 @@
+package channels
+
+import (
+	"context"
+	"time"
+)
+
+// RetryOption is an option that can be provided to Retry. See also WithClock.
+type RetryOption interface {
+	applyRetry(*retryOptions)
+}
+
+type retryOptions struct {
+	timeOptions
+	backoff     func(attempt int) time.Duration
+	minInterval time.Duration
+}
+
+type retryOption func(*retryOptions)
+
+func (o retryOption) applyRetry(opts *retryOptions) {
+	o(opts)
+}
+
+// WithBackoff sets a function that determines how long Retry waits before
+// each resubscription. The function is called with the number of the
+// resubscription, starting at 1.
+//
+// The default behavior is to resubscribe immediately.
+func WithBackoff(backoff func(attempt int) time.Duration) RetryOption {
+	return retryOption(func(opts *retryOptions) {
+		opts.backoff = backoff
+	})
+}
+
+// WithMinInterval sets the minimum time between two consecutive calls to the
+// source function, so that sources that complete quickly, such as polling
+// sources, aren't called in a tight loop. It's combined with WithBackoff, if
+// provided, by waiting for the backoff and then for whatever is left of the
+// minimum interval.
+//
+// The default value is 0, meaning that there's no minimum interval.
+func WithMinInterval(d time.Duration) RetryOption {
+	return retryOption(func(opts *retryOptions) {
+		opts.minInterval = d
+	})
+}
+
+// Retry subscribes to a source by calling the source function and returns an
+// output channel that emits the values from the channel returned by the
+// source. Whenever that channel is closed before the provided context is
+// cancelled, Retry calls the source function again to get a fresh channel, up
+// to attempts times. Negative values of attempts are treated as zero, which
+// makes Retry forward the values from a single subscription.
+//
+// The source function is called with the provided context, which allows it to
+// release its resources on cancellation.
+//
+// The output channel is unbuffered.
+//
+// See WithClock for changing the clock used to wait between subscriptions.
+//
+// This is a non-blocking function: it launches a goroutine and returns the
+// channel for consumption. In order to stop the inner goroutine, one can
+// cancel the provided context.
+//
+// The output channel is always closed on cancellation or after the channel of
+// the last subscription is closed.
+func Retry[T any](ctx context.Context, attempts int, source func(context.Context) <-chan T, opts ...RetryOption) <-chan T {
+	if attempts < 0 {
+		attempts = 0
+	}
+	return retry(ctx, attempts, source, opts)
+}
+
+// KeepAlive is like Retry, but it calls the source function again every time
+// the channel it returns is closed, for as long as the provided context isn't
+// cancelled, turning a source of finite channels into an infinite channel.
+//
+// The output channel is unbuffered.
+//
+// See WithClock for changing the clock used to wait between subscriptions.
+//
+// This is a non-blocking function: it launches a goroutine and returns the
+// channel for consumption. In order to stop the inner goroutine, one can
+// cancel the provided context.
+//
+// The output channel is always closed on cancellation.
+func KeepAlive[T any](ctx context.Context, source func(context.Context) <-chan T, opts ...RetryOption) <-chan T {
+	return retry(ctx, -1, source, opts)
+}
+
+// retry subscribes to the source up to attempts+1 times, or indefinitely if
+// attempts is negative.
+func retry[T any](ctx context.Context, attempts int, source func(context.Context) <-chan T, opts []RetryOption) <-chan T {
+	options := retryOptions{timeOptions: defaultTimeOptions()}
+	for _, opt := range opts {
+		opt.applyRetry(&options)
+	}
+	out := make(chan T)
+	go func() {
+		defer close(out)
+		timer := lazyTimer{clock: options.clock}
+		defer timer.Stop()
+		var lastCall time.Time
+		for attempt := 0; attempts < 0 || attempt <= attempts; attempt++ {
+			if attempt > 0 {
+				if options.backoff != nil && !sleep(ctx, &timer, options.backoff(attempt)) {
+					return
+				}
+				if !sleep(ctx, &timer, options.minInterval-options.clock.Now().Sub(lastCall)) {
+					return
+				}
+			}
+			lastCall = options.clock.Now()
+			receiveLoop(ctx, source(ctx), func(v T) bool {
+				return trySend(ctx, out, v)
+			})
+			if ctx.Err() != nil {
+				return
+			}
+		}
+	}()
+	return out
+}
+
+// sleep waits for the provided duration using the provided timer, returning
+// false if the context is cancelled before that.
+func sleep(ctx context.Context, timer Timer, d time.Duration) bool {
+	if d <= 0 {
+		return ctx.Err() == nil
+	}
+	timer.Reset(d)
+	select {
+	case <-timer.C():
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
@@ -0,0 +1,180 @@
+package channels
+
+import (
+	"context"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestRetry(t *testing.T) {
+	t.Parallel()
+	subscriptions := 0
+	source := func(ctx context.Context) <-chan int {
+		subscriptions++
+		base := subscriptions * 10
+		return fromSlice(t, []int{base + 1, base + 2})
+	}
+
+	values := ToSlice(context.TODO(), Retry(context.TODO(), 2, source))
+	expected := []int{11, 12, 21, 22, 31, 32}
+	if !reflect.DeepEqual(values, expected) {
+		t.Errorf("wrong values returned\nwant %#v\ngot  %#v", expected, values)
+	}
+	if subscriptions != 3 {
+		t.Errorf("wrong number of subscriptions\nwant 3\ngot  %d", subscriptions)
+	}
+}
+
+func TestRetryWithBackoff(t *testing.T) {
+	t.Parallel()
+	var attempts []int
+	backoff := func(attempt int) time.Duration {
+		attempts = append(attempts, attempt)
+		return time.Duration(attempt) * time.Second
+	}
+	delays := make(chan time.Duration, 10)
+	timers := make(chan chan time.Time, 10)
+	after := func(d time.Duration) <-chan time.Time {
+		timer := make(chan time.Time, 1)
+		delays <- d
+		timers <- timer
+		return timer
+	}
+	source := func(ctx context.Context) <-chan int {
+		return fromSlice(t, []int{1})
+	}
+
+	out := Retry(context.TODO(), 2, source, WithBackoff(backoff), WithClock(testClock{after: after}))
+	var values []int
+	for _, delay := range []time.Duration{time.Second, 2 * time.Second} {
+		values = append(values, <-out)
+		if d := <-delays; d != delay {
+			t.Errorf("wrong backoff delay\nwant %s\ngot  %s", delay, d)
+		}
+		(<-timers) <- time.Now()
+	}
+	values = append(values, ToSlice(context.TODO(), out)...)
+
+	expected := []int{1, 1, 1}
+	if !reflect.DeepEqual(values, expected) {
+		t.Errorf("wrong values returned\nwant %#v\ngot  %#v", expected, values)
+	}
+	expectedAttempts := []int{1, 2}
+	if !reflect.DeepEqual(attempts, expectedAttempts) {
+		t.Errorf("wrong attempts passed to backoff\nwant %#v\ngot  %#v", expectedAttempts, attempts)
+	}
+}
+
+func TestRetryWithContextCancellation(t *testing.T) {
+	t.Parallel()
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	source := func(ctx context.Context) <-chan int {
+		return fromSlice(t, []int{1})
+	}
+
+	values := ToSlice(context.TODO(), Retry(ctx, 1000, source, WithBackoff(func(int) time.Duration {
+		return time.Second
+	})))
+	expected := []int{1}
+	if !reflect.DeepEqual(values, expected) {
+		t.Errorf("wrong values returned\nwant %#v\ngot  %#v", expected, values)
+	}
+}
+
+func TestKeepAlive(t *testing.T) {
+	t.Parallel()
+	calls := 0
+	source := func(ctx context.Context) <-chan int {
+		calls++
+		base := calls * 10
+		return fromSlice(t, []int{base + 1, base + 2})
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	values := ToSlice(context.TODO(), Take(context.TODO(), KeepAlive(ctx, source), 5))
+	expected := []int{11, 12, 21, 22, 31}
+	if !reflect.DeepEqual(values, expected) {
+		t.Errorf("wrong values returned\nwant %#v\ngot  %#v", expected, values)
+	}
+}
+
+func TestKeepAliveWithMinInterval(t *testing.T) {
+	t.Parallel()
+	var mu sync.Mutex
+	current := time.Date(2021, time.December, 1, 0, 0, 0, 0, time.UTC)
+	now := func() time.Time {
+		mu.Lock()
+		defer mu.Unlock()
+		return current
+	}
+	advance := func(d time.Duration) {
+		mu.Lock()
+		defer mu.Unlock()
+		current = current.Add(d)
+	}
+	delays := make(chan time.Duration, 10)
+	timers := make(chan chan time.Time, 10)
+	after := func(d time.Duration) <-chan time.Time {
+		timer := make(chan time.Time, 1)
+		delays <- d
+		timers <- timer
+		return timer
+	}
+	// each subscription takes the given time to complete.
+	durations := []time.Duration{0, 300 * time.Millisecond, 2 * time.Second, 0}
+	calls := 0
+	source := func(ctx context.Context) <-chan int {
+		advance(durations[calls])
+		calls++
+		return fromSlice(t, []int{calls})
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	out := KeepAlive(ctx, source, WithMinInterval(time.Second), WithClock(testClock{now: now, after: after}))
+
+	// the first subscription completes right away, so the source is only
+	// called again once the whole interval has elapsed, and the second
+	// subscription takes part of the interval.
+	for _, delay := range []time.Duration{time.Second, 700 * time.Millisecond} {
+		<-out
+		if d := <-delays; d != delay {
+			t.Errorf("wrong delay\nwant %s\ngot  %s", delay, d)
+		}
+		advance(delay)
+		(<-timers) <- now()
+	}
+
+	// the third subscription takes longer than the interval, so there's no
+	// wait before the fourth.
+	if v := <-out; v != 3 {
+		t.Errorf("wrong value returned\nwant 3\ngot  %d", v)
+	}
+	select {
+	case v := <-out:
+		if v != 4 {
+			t.Errorf("wrong value returned\nwant 4\ngot  %d", v)
+		}
+	case d := <-delays:
+		t.Errorf("unexpected wait of %s after the interval elapsed", d)
+	}
+}
+
+func TestKeepAliveWithContextCancellation(t *testing.T) {
+	t.Parallel()
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	source := func(ctx context.Context) <-chan int {
+		return fromSlice(t, []int{1})
+	}
+
+	values := ToSlice(context.TODO(), KeepAlive(ctx, source, WithMinInterval(time.Second)))
+	expected := []int{1}
+	if !reflect.DeepEqual(values, expected) {
+		t.Errorf("wrong values returned\nwant %#v\ngot  %#v", expected, values)
+	}
+}
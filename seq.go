@@ -0,0 +1,38 @@
+//go:build go1.23
+
+package channels
+
+import (
+	"context"
+	"iter"
+)
+
+// FlatMapSeq takes an input channel and a function that expands each value of
+// the input type into a sequence of values of the output type, and returns a
+// channel that emits the values of each sequence, in order. Sequences are
+// consumed lazily, as values are sent to the output channel, so they can be
+// arbitrarily long or even infinite.
+//
+// The capacity of the output channel will be same as the capacity of the input
+// channel.
+//
+// This is a non-blocking function: it launches a goroutine and returns the
+// channel for consumption. In order to stop the inner goroutine, one can close
+// the input channel or cancel the provided context. On cancellation, the
+// iteration of the current sequence is stopped.
+//
+// The output channel is always closed on cancellation, even if the input
+// channel is never closed.
+func FlatMapSeq[InputType, OutputType any](ctx context.Context, in <-chan InputType, f func(InputType) iter.Seq[OutputType]) <-chan OutputType {
+	out := make(chan OutputType, cap(in))
+	go func() {
+		defer close(out)
+		receiveLoop(ctx, in, func(v InputType) bool {
+			f(v)(func(o OutputType) bool {
+				return trySend(ctx, out, o)
+			})
+			return ctx.Err() == nil
+		})
+	}()
+	return out
+}
@@ -0,0 +1,82 @@
+//go:build go1.23
+
+package channels
+
+import (
+	"context"
+	"iter"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestFlatMapSeq(t *testing.T) {
+	t.Parallel()
+	ch := startGenerator(t, 0, func(p int) (int, bool) {
+		if p > 2 {
+			return p, false
+		}
+		return p + 1, true
+	}, nil)
+
+	values := FlatMapSeq(context.TODO(), ch, func(v int) iter.Seq[int] {
+		return func(yield func(int) bool) {
+			for i := 0; i < v; i++ {
+				if !yield(v) {
+					return
+				}
+			}
+		}
+	})
+
+	expected := []int{1, 2, 2, 3, 3, 3}
+	if got := ToSlice(context.TODO(), values); !reflect.DeepEqual(got, expected) {
+		t.Errorf("wrong values returned\nwant %#v\ngot  %#v", expected, got)
+	}
+}
+
+func TestFlatMapSeqUnbounded(t *testing.T) {
+	t.Parallel()
+	ch := startGenerator(t, 0, func(p int) (int, bool) {
+		return p + 1, true
+	}, nil)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	stopped := make(chan struct{})
+	values := FlatMapSeq(ctx, ch, func(v int) iter.Seq[int] {
+		return func(yield func(int) bool) {
+			defer close(stopped)
+			for i := v; yield(i); i++ {
+			}
+		}
+	})
+
+	expected := []int{1, 2, 3, 4, 5}
+	if got := ToSlice(context.TODO(), Take(context.TODO(), values, 5)); !reflect.DeepEqual(got, expected) {
+		t.Errorf("wrong values returned\nwant %#v\ngot  %#v", expected, got)
+	}
+
+	cancel()
+	select {
+	case <-stopped:
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for the sequence to stop after cancellation")
+	}
+}
+
+func TestFlatMapSeqWithContextCancellation(t *testing.T) {
+	t.Parallel()
+	ch := startGenerator(t, "", func(v string) (string, bool) {
+		return v, true
+	}, func() { time.Sleep(time.Second) })
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	values := FlatMapSeq(ctx, ch, func(v string) iter.Seq[string] {
+		return func(yield func(string) bool) { yield(v) }
+	})
+	if got := ToSlice(context.TODO(), values); got != nil {
+		t.Errorf("unexpected non-nil slice: %#v", got)
+	}
+}
@@ -0,0 +1,243 @@
+package channels
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+// Codec encodes and decodes values of type T to and from bytes. Each value is
+// encoded on its own, so the output of Marshal must contain everything
+// Unmarshal needs to decode the value.
+type Codec[T any] interface {
+	Marshal(v T) ([]byte, error)
+	Unmarshal(data []byte) (T, error)
+}
+
+// JSONCodec is a Codec that encodes values as JSON.
+type JSONCodec[T any] struct{}
+
+// Marshal encodes the value as JSON.
+func (JSONCodec[T]) Marshal(v T) ([]byte, error) {
+	return json.Marshal(v)
+}
+
+// Unmarshal decodes the value from JSON.
+func (JSONCodec[T]) Unmarshal(data []byte) (T, error) {
+	var v T
+	err := json.Unmarshal(data, &v)
+	return v, err
+}
+
+// SpoolOption is an option that can be provided to SpoolBuffer.
+type SpoolOption func(*spoolOptions)
+
+type spoolOptions struct {
+	errorHandler func(error)
+}
+
+// WithSpoolErrorHandler sets a function that SpoolBuffer calls with each
+// error it runs into: values that can't be encoded or decoded with the codec,
+// and failures to create, write or read the temporary file.
+//
+// The function is called from the goroutine of the operator, so it should not
+// block. It may cancel the context provided to SpoolBuffer, for example to
+// stop instead of buffering in memory when the temporary file can't be
+// written.
+//
+// The default behavior is to ignore errors.
+func WithSpoolErrorHandler(handler func(error)) SpoolOption {
+	return func(opts *spoolOptions) {
+		opts.errorHandler = handler
+	}
+}
+
+// SpoolBuffer takes an input channel and returns an output channel that emits
+// the values from the input channel in the same order, buffering them so
+// that the input channel is never blocked by slow consumers. Up to memLimit
+// values are buffered in memory, and further values are encoded with the
+// provided codec and spooled to a temporary file, which is read back as the
+// consumer catches up and removed once drained. If memLimit is lower than 1,
+// a single value is kept in memory.
+//
+// Values that can't be encoded or decoded with the codec are discarded. If the
+// temporary file can't be created or written, the values spooled so far are
+// read back and SpoolBuffer falls back to buffering in memory, so memory usage
+// is no longer bounded. If the temporary file can't be read, the values
+// spooled to it are lost. See WithSpoolErrorHandler for observing these
+// errors.
+//
+// The output channel is unbuffered.
+//
+// This is a non-blocking function: it launches a goroutine and returns the
+// channel for consumption. In order to stop the inner goroutine, one can close
+// the input channel or cancel the provided context.
+//
+// The output channel is always closed on cancellation or after the input
+// channel is closed and all buffered values are sent. Buffered values are
+// discarded on cancellation.
+func SpoolBuffer[T any](ctx context.Context, in <-chan T, memLimit int, codec Codec[T], opts ...SpoolOption) <-chan T {
+	return spoolBuffer(ctx, in, memLimit, codec, "", opts...)
+}
+
+func spoolBuffer[T any](ctx context.Context, in <-chan T, memLimit int, codec Codec[T], dir string, opts ...SpoolOption) <-chan T {
+	var options spoolOptions
+	for _, opt := range opts {
+		opt(&options)
+	}
+	if memLimit < 1 {
+		memLimit = 1
+	}
+	out := make(chan T)
+	go func() {
+		defer close(out)
+		s := &spool[T]{codec: codec, dir: dir, errorHandler: options.errorHandler}
+		defer s.remove()
+
+		var mem []T
+		for in != nil || len(mem) > 0 || s.count > 0 {
+			for len(mem) == 0 && s.count > 0 {
+				if v, ok := s.pop(); ok {
+					mem = append(mem, v)
+				}
+			}
+
+			var (
+				sendCh chan<- T
+				next   T
+			)
+			if len(mem) > 0 {
+				sendCh = out
+				next = mem[0]
+			}
+			select {
+			case v, ok := <-in:
+				if !ok {
+					in = nil
+					continue
+				}
+				if s.failed || (s.count == 0 && len(mem) < memLimit) {
+					mem = append(mem, v)
+				} else if !s.push(v) {
+					mem = append(mem, s.drain()...)
+					mem = append(mem, v)
+				}
+			case sendCh <- next:
+				var zero T
+				mem[0] = zero
+				mem = mem[1:]
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return out
+}
+
+// spool is a FIFO queue of encoded values backed by a temporary file. Each
+// value is stored as a 4-byte big-endian length followed by the encoded
+// value.
+type spool[T any] struct {
+	codec  Codec[T]
+	dir    string
+	file   *os.File
+	offset int64
+	end    int64
+	count  int
+	failed bool
+
+	errorHandler func(error)
+}
+
+func (s *spool[T]) reportError(op string, err error) {
+	if s.errorHandler != nil {
+		s.errorHandler(fmt.Errorf("channels: SpoolBuffer: %s: %w", op, err))
+	}
+}
+
+// push adds a value to the end of the queue. It returns false, and marks the
+// spool as failed, if the value can't be written to the file. Values that
+// can't be encoded are discarded. Errors are reported to the error handler.
+func (s *spool[T]) push(v T) bool {
+	data, err := s.codec.Marshal(v)
+	if err != nil {
+		s.reportError("encoding value", err)
+		return true
+	}
+	if s.file == nil {
+		if s.file, err = os.CreateTemp(s.dir, "channels-spool-*"); err != nil {
+			s.reportError("creating spool file", err)
+			s.failed = true
+			return false
+		}
+	}
+	record := make([]byte, 4+len(data))
+	binary.BigEndian.PutUint32(record, uint32(len(data)))
+	copy(record[4:], data)
+	if _, err := s.file.WriteAt(record, s.end); err != nil {
+		s.reportError("writing spool file", err)
+		s.failed = true
+		return false
+	}
+	s.end += int64(len(record))
+	s.count++
+	return true
+}
+
+// pop removes a value from the start of the queue, removing the file once the
+// queue is empty. It returns false if the value can't be read or decoded,
+// reporting the error to the error handler. If the file can't be read, all
+// the values in the queue are discarded.
+func (s *spool[T]) pop() (T, bool) {
+	var zero T
+	s.count--
+	defer func() {
+		if s.count == 0 {
+			s.remove()
+		}
+	}()
+
+	var header [4]byte
+	if _, err := s.file.ReadAt(header[:], s.offset); err != nil {
+		s.reportError("reading spool file", err)
+		s.count = 0
+		return zero, false
+	}
+	data := make([]byte, binary.BigEndian.Uint32(header[:]))
+	if _, err := s.file.ReadAt(data, s.offset+4); err != nil {
+		s.reportError("reading spool file", err)
+		s.count = 0
+		return zero, false
+	}
+	s.offset += int64(4 + len(data))
+	v, err := s.codec.Unmarshal(data)
+	if err != nil {
+		s.reportError("decoding value", err)
+		return zero, false
+	}
+	return v, true
+}
+
+// drain removes and returns all values in the queue.
+func (s *spool[T]) drain() []T {
+	var values []T
+	for s.count > 0 {
+		if v, ok := s.pop(); ok {
+			values = append(values, v)
+		}
+	}
+	return values
+}
+
+func (s *spool[T]) remove() {
+	if s.file != nil {
+		s.file.Close()
+		os.Remove(s.file.Name())
+		s.file = nil
+	}
+	s.offset = 0
+	s.end = 0
+	s.count = 0
+}
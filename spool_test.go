@@ -0,0 +1,165 @@
+package channels
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"os"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestSpoolBuffer(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	in := make(chan jsonRecord)
+	out := spoolBuffer[jsonRecord](context.TODO(), in, 2, JSONCodec[jsonRecord]{}, dir)
+
+	var expected []jsonRecord
+	for i := 0; i < 10; i++ {
+		v := jsonRecord{Name: "item", Count: i}
+		expected = append(expected, v)
+		select {
+		case in <- v:
+		case <-time.After(time.Second):
+			t.Fatalf("timed out sending value %d", i)
+		}
+	}
+	close(in)
+
+	if entries, err := os.ReadDir(dir); err != nil {
+		t.Fatal(err)
+	} else if len(entries) != 1 {
+		t.Errorf("wrong number of spool files\nwant 1\ngot  %d", len(entries))
+	}
+
+	values := ToSlice(context.TODO(), out)
+	if !reflect.DeepEqual(values, expected) {
+		t.Errorf("wrong values returned\nwant %#v\ngot  %#v", expected, values)
+	}
+
+	if entries, err := os.ReadDir(dir); err != nil {
+		t.Fatal(err)
+	} else if len(entries) != 0 {
+		t.Errorf("spool file not removed after draining: %v", entries)
+	}
+}
+
+func TestSpoolBufferInterleaved(t *testing.T) {
+	t.Parallel()
+	in := make(chan int)
+	out := spoolBuffer[int](context.TODO(), in, 1, JSONCodec[int]{}, t.TempDir())
+
+	var got []int
+	next := 0
+	for round := 0; round < 3; round++ {
+		for i := 0; i < 4; i++ {
+			in <- next
+			next++
+		}
+		got = append(got, <-out, <-out)
+	}
+	close(in)
+	got = append(got, ToSlice(context.TODO(), out)...)
+
+	expected := []int{0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("wrong values returned\nwant %#v\ngot  %#v", expected, got)
+	}
+}
+
+func TestSpoolBufferFallsBackToMemory(t *testing.T) {
+	t.Parallel()
+	in := make(chan int)
+	dir := t.TempDir()
+	if err := os.Remove(dir); err != nil {
+		t.Fatal(err)
+	}
+	var errs []error
+	out := spoolBuffer[int](context.TODO(), in, 1, JSONCodec[int]{}, dir, WithSpoolErrorHandler(func(err error) {
+		errs = append(errs, err)
+	}))
+
+	for i := 0; i < 5; i++ {
+		in <- i
+	}
+	close(in)
+
+	expected := []int{0, 1, 2, 3, 4}
+	if values := ToSlice(context.TODO(), out); !reflect.DeepEqual(values, expected) {
+		t.Errorf("wrong values returned\nwant %#v\ngot  %#v", expected, values)
+	}
+	if len(errs) != 1 || !errors.Is(errs[0], fs.ErrNotExist) {
+		t.Errorf("wrong errors reported\nwant a single fs.ErrNotExist\ngot  %v", errs)
+	}
+}
+
+// failingCodec is a JSONCodec that fails to encode 2 and to decode 3.
+type failingCodec struct {
+	JSONCodec[int]
+}
+
+var errCodec = errors.New("codec failure")
+
+func (c failingCodec) Marshal(v int) ([]byte, error) {
+	if v == 2 {
+		return nil, errCodec
+	}
+	return c.JSONCodec.Marshal(v)
+}
+
+func (c failingCodec) Unmarshal(data []byte) (int, error) {
+	if string(data) == "3" {
+		return 0, errCodec
+	}
+	return c.JSONCodec.Unmarshal(data)
+}
+
+func TestSpoolBufferReportsCodecErrors(t *testing.T) {
+	t.Parallel()
+	in := make(chan int)
+	var errs []error
+	out := spoolBuffer[int](context.TODO(), in, 1, failingCodec{}, t.TempDir(), WithSpoolErrorHandler(func(err error) {
+		errs = append(errs, err)
+	}))
+
+	// 0 is kept in memory and the other values are spooled.
+	for i := 0; i < 5; i++ {
+		in <- i
+	}
+	close(in)
+
+	expected := []int{0, 1, 4}
+	if values := ToSlice(context.TODO(), out); !reflect.DeepEqual(values, expected) {
+		t.Errorf("wrong values returned\nwant %#v\ngot  %#v", expected, values)
+	}
+	expectedErrs := []string{
+		"channels: SpoolBuffer: encoding value: codec failure",
+		"channels: SpoolBuffer: decoding value: codec failure",
+	}
+	var gotErrs []string
+	for _, err := range errs {
+		if !errors.Is(err, errCodec) {
+			t.Errorf("unexpected error: %v", err)
+		}
+		gotErrs = append(gotErrs, err.Error())
+	}
+	if !reflect.DeepEqual(gotErrs, expectedErrs) {
+		t.Errorf("wrong errors reported\nwant %#v\ngot  %#v", expectedErrs, gotErrs)
+	}
+}
+
+func TestSpoolBufferWithContextCancellation(t *testing.T) {
+	t.Parallel()
+	ch := startGenerator(t, "", func(v string) (string, bool) {
+		return v, true
+	}, func() { time.Sleep(time.Second) })
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	values := ToSlice(context.TODO(), SpoolBuffer[string](ctx, ch, 2, JSONCodec[string]{}))
+	if values != nil {
+		t.Errorf("unexpected non-nil slice: %#v", values)
+	}
+}
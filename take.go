@@ -1,6 +1,9 @@
 package channels
 
-import "context"
+import (
+	"context"
+	"time"
+)
 
 // Take takes an input channel and returns an output channel that will contain
 // at most N elements from the input channel.
@@ -16,11 +19,12 @@ import "context"
 func Take[T any](ctx context.Context, in <-chan T, n uint) <-chan T {
 	maxLen := int(n)
 	out := make(chan T, min(maxLen, cap(in)))
+	if maxLen == 0 {
+		close(out)
+		return out
+	}
 	go func() {
 		defer close(out)
-		if maxLen == 0 {
-			return
-		}
 		length := 0
 		receiveLoop(ctx, in, func(v T) bool {
 			if !trySend(ctx, out, v) {
@@ -78,3 +82,232 @@ func TakeUntil[T any](ctx context.Context, in <-chan T, f func(T) bool) <-chan T
 		return !f(v)
 	})
 }
+
+// ThrottleOption is an option that can be provided to TakeEvery. See
+// WithDropCounter and WithClock.
+type ThrottleOption interface {
+	applyThrottle(*throttleOptions)
+}
+
+type throttleOptions struct {
+	timeOptions
+	dropOptions
+}
+
+func newThrottleOptions(opts []ThrottleOption) throttleOptions {
+	options := throttleOptions{timeOptions: defaultTimeOptions()}
+	for _, opt := range opts {
+		opt.applyThrottle(&options)
+	}
+	return options
+}
+
+// DropOption is an option that can be provided to operators that discard
+// values, such as TeeLossy. It's also accepted by TakeEvery.
+type DropOption interface {
+	ThrottleOption
+	applyDrop(*dropOptions)
+}
+
+type dropOptions struct {
+	dropCounter func(dropped int)
+}
+
+type dropOption func(*dropOptions)
+
+func (o dropOption) applyDrop(opts *dropOptions) {
+	o(opts)
+}
+
+func (o dropOption) applyThrottle(opts *throttleOptions) {
+	o(&opts.dropOptions)
+}
+
+// WithDropCounter sets a function that is called with the number of elements
+// discarded by the operator, making it possible to observe how lossy the
+// operator is.
+//
+// Discarded elements are reported in batches: the function is called with
+// the number of elements discarded since the previous call whenever the
+// operator forwards an element after discarding some, and once more when the
+// input channel is closed or the context is cancelled. It's never called with
+// 0.
+//
+// The default behavior is to not report discarded elements.
+func WithDropCounter(f func(dropped int)) DropOption {
+	return dropOption(func(opts *dropOptions) {
+		opts.dropCounter = f
+	})
+}
+
+func newDropOptions(opts []DropOption) dropOptions {
+	var options dropOptions
+	for _, opt := range opts {
+		opt.applyDrop(&options)
+	}
+	return options
+}
+
+// dropReporter accumulates the number of discarded elements between calls to
+// the drop counter.
+type dropReporter struct {
+	dropCounter func(dropped int)
+	dropped     int
+}
+
+func (r *dropReporter) drop() {
+	r.dropped++
+}
+
+func (r *dropReporter) report() {
+	if r.dropped > 0 && r.dropCounter != nil {
+		r.dropCounter(r.dropped)
+	}
+	r.dropped = 0
+}
+
+// TakeEvery takes an input channel and returns an output channel that will
+// emit at most one element per interval of duration d, regardless of the rate
+// of the input channel. The first element received after each interval
+// boundary is forwarded, and every other element received within the same
+// interval is discarded. See WithDropCounter for observing the number of
+// discarded elements and WithClock for changing the clock used to measure
+// intervals.
+//
+// The capacity of the output channel will be cap(inputChannel).
+//
+// This is a non-blocking function: it launches a goroutine and returns the
+// channel for consumption. In order to stop the inner goroutine, one can close
+// the input channel or cancel the provided context.
+//
+// The output channel is always closed on cancellation, even if the input
+// channel is never closed.
+func TakeEvery[T any](ctx context.Context, in <-chan T, d time.Duration, opts ...ThrottleOption) <-chan T {
+	options := newThrottleOptions(opts)
+	var next time.Time
+	drops := dropReporter{dropCounter: options.dropCounter}
+	out := make(chan T, cap(in))
+	go func() {
+		defer close(out)
+		defer drops.report()
+		receiveLoop(ctx, in, func(v T) bool {
+			t := options.clock.Now()
+			if t.Before(next) {
+				drops.drop()
+				return true
+			}
+			drops.report()
+			next = t.Add(d)
+			return trySend(ctx, out, v)
+		})
+	}()
+	return out
+}
+
+// TakeAny takes a list of input channels and returns an output channel that
+// will contain at most N elements, received from any of the input channels in
+// the order they arrive. Input channels are read concurrently, so after N
+// elements are sent, up to one extra element from each input channel may have
+// been received and is discarded.
+//
+// The output channel is unbuffered.
+//
+// This is a non-blocking function: it launches goroutines and returns the
+// channel for consumption. In order to stop the inner goroutines, one can close
+// all input channels or cancel the provided context.
+//
+// The output channel is always closed on cancellation or after sending N
+// elements, even if the input channels are never closed. In both cases, the
+// output channel is only closed after all the goroutines reading from the
+// input channels have returned.
+func TakeAny[T any](ctx context.Context, ins []<-chan T, n uint) <-chan T {
+	out := make(chan T)
+	go func() {
+		defer close(out)
+		if n == 0 {
+			return
+		}
+		mergeCtx, cancel := context.WithCancel(ctx)
+		merged := merge(mergeCtx, ins, operatorOptions{})
+		var taken uint
+		receiveLoop(ctx, merged, func(v T) bool {
+			if !trySend(ctx, out, v) {
+				return false
+			}
+			taken++
+			return taken < n
+		})
+		cancel()
+		for range merged {
+		}
+	}()
+	return out
+}
+
+// TakeWeighted takes an input channel and returns an output channel that will
+// emit values while their cumulative weight, as returned by the weight
+// function, doesn't exceed the budget. Like TakeWhile, it discards the value
+// of the first element that would take the cumulative weight over the budget,
+// even if later elements would fit in the remaining budget.
+//
+// The capacity of the output channel will be cap(inputChannel).
+//
+// This is a non-blocking function: it launches a goroutine and returns the
+// channel for consumption. In order to stop the inner goroutine, one can close
+// the input channel or cancel the provided context.
+//
+// The output channel is always closed on cancellation or after an element
+// exceeds the budget, even if the input channel is never closed.
+func TakeWeighted[T any](ctx context.Context, in <-chan T, budget int, weight func(T) int) <-chan T {
+	total := 0
+	return TakeWhile(ctx, in, func(v T) bool {
+		total += weight(v)
+		return total <= budget
+	})
+}
+
+// TakeUntilSignal takes an input channel and returns an output channel that
+// will emit values from the input channel until the stop channel is closed or
+// receives a value. A value received from the input channel when the stop
+// channel fires, including one waiting to be sent to the output channel, is
+// discarded. A nil stop channel never fires, so all values are emitted.
+//
+// The capacity of the output channel will be cap(inputChannel).
+//
+// This is a non-blocking function: it launches a goroutine and returns the
+// channel for consumption. In order to stop the inner goroutine, one can close
+// the input channel, fire the stop channel or cancel the provided context.
+//
+// The output channel is always closed on cancellation or after the stop
+// channel fires, even if the input channel is never closed.
+func TakeUntilSignal[T any](ctx context.Context, in <-chan T, stop <-chan struct{}) <-chan T {
+	out := make(chan T, cap(in))
+	go func() {
+		defer close(out)
+		for {
+			select {
+			case <-stop:
+				return
+			default:
+			}
+			select {
+			case v, ok := <-in:
+				if !ok {
+					return
+				}
+				select {
+				case out <- v:
+				case <-stop:
+					return
+				case <-ctx.Done():
+					return
+				}
+			case <-stop:
+				return
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return out
+}
@@ -3,6 +3,8 @@ package channels
 import (
 	"context"
 	"reflect"
+	"runtime"
+	"sort"
 	"testing"
 	"time"
 )
@@ -40,6 +42,21 @@ func TestTake(t *testing.T) {
 	})
 }
 
+// TestTakeZeroNoGoroutine doesn't run in parallel, so other tests don't
+// affect the number of goroutines.
+func TestTakeZeroNoGoroutine(t *testing.T) {
+	in := make(chan int)
+	before := runtime.NumGoroutine()
+	out := Take(context.TODO(), in, 0)
+	after := runtime.NumGoroutine()
+	if after != before {
+		t.Errorf("wrong number of goroutines after Take(0)\nwant %d\ngot  %d", before, after)
+	}
+	if _, ok := <-out; ok {
+		t.Error("unexpected value from Take(0)")
+	}
+}
+
 func TestTakeWithContextCancellation(t *testing.T) {
 	t.Parallel()
 	ch := startGenerator(t, "", func(v string) (string, bool) {
@@ -166,3 +183,217 @@ func TestTakeUntilWithContextCancellation(t *testing.T) {
 		t.Errorf("wrong values returned\nwant %#v\ngot  %#v", expectedSlice, values)
 	}
 }
+
+func TestTakeEvery(t *testing.T) {
+	t.Parallel()
+
+	t.Run("forwards a single element from a burst within one interval", func(t *testing.T) {
+		ch := startGenerator(t, 0, func(p int) (int, bool) {
+			if p > 9 {
+				return p, false
+			}
+			return p + 1, true
+		}, nil)
+		start := time.Now()
+		now := func() time.Time { return start }
+
+		values := ToSlice(context.TODO(), TakeEvery(context.TODO(), ch, time.Second, WithClock(testClock{now: now})))
+		expectedSlice := []int{1}
+		if !reflect.DeepEqual(values, expectedSlice) {
+			t.Errorf("wrong values returned\nwant %#v\ngot  %#v", expectedSlice, values)
+		}
+	})
+
+	t.Run("forwards the first element after each interval boundary", func(t *testing.T) {
+		ch := startGenerator(t, 0, func(p int) (int, bool) {
+			if p > 8 {
+				return p, false
+			}
+			return p + 1, true
+		}, nil)
+		current := time.Now()
+		now := func() time.Time {
+			t := current
+			current = current.Add(10 * time.Millisecond)
+			return t
+		}
+
+		values := ToSlice(context.TODO(), TakeEvery(context.TODO(), ch, 35*time.Millisecond, WithClock(testClock{now: now})))
+		expectedSlice := []int{1, 5, 9}
+		if !reflect.DeepEqual(values, expectedSlice) {
+			t.Errorf("wrong values returned\nwant %#v\ngot  %#v", expectedSlice, values)
+		}
+	})
+}
+
+func TestTakeEveryWithDropCounter(t *testing.T) {
+	t.Parallel()
+	ch := startGenerator(t, 0, func(p int) (int, bool) {
+		if p > 9 {
+			return p, false
+		}
+		return p + 1, true
+	}, nil)
+	current := time.Now()
+	now := func() time.Time {
+		t := current
+		current = current.Add(10 * time.Millisecond)
+		return t
+	}
+
+	var reports []int
+	counter := WithDropCounter(func(dropped int) {
+		reports = append(reports, dropped)
+	})
+	values := ToSlice(context.TODO(), TakeEvery(context.TODO(), ch, 35*time.Millisecond, WithClock(testClock{now: now}), counter))
+	expectedSlice := []int{1, 5, 9}
+	if !reflect.DeepEqual(values, expectedSlice) {
+		t.Errorf("wrong values returned\nwant %#v\ngot  %#v", expectedSlice, values)
+	}
+	expectedReports := []int{3, 3, 1}
+	if !reflect.DeepEqual(reports, expectedReports) {
+		t.Errorf("wrong drop counts reported\nwant %#v\ngot  %#v", expectedReports, reports)
+	}
+}
+
+func TestTakeEveryWithContextCancellation(t *testing.T) {
+	t.Parallel()
+	ch := startGenerator(t, "", func(v string) (string, bool) {
+		return v, true
+	}, func() { time.Sleep(time.Second) })
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	values := ToSlice(context.TODO(), TakeEvery(ctx, ch, time.Millisecond))
+	expectedSlice := []string(nil)
+	if !reflect.DeepEqual(values, expectedSlice) {
+		t.Errorf("wrong values returned\nwant %#v\ngot  %#v", expectedSlice, values)
+	}
+}
+
+func TestTakeAny(t *testing.T) {
+	t.Parallel()
+	gen := func(start int) <-chan int {
+		return startGenerator(t, start, func(p int) (int, bool) {
+			return p + 1, true
+		}, nil)
+	}
+	ins := []<-chan int{gen(0), gen(100), gen(200)}
+
+	t.Run("takes exactly N elements across sources", func(t *testing.T) {
+		values := ToSlice(context.TODO(), TakeAny(context.TODO(), ins, 7))
+		if len(values) != 7 {
+			t.Errorf("wrong number of values returned\nwant 7\ngot  %d (%#v)", len(values), values)
+		}
+	})
+
+	t.Run("can take 0 elements", func(t *testing.T) {
+		values := ToSlice(context.TODO(), TakeAny(context.TODO(), ins, 0))
+		if values != nil {
+			t.Errorf("unexpected non-nil slice from TakeAny(0): %#v", values)
+		}
+	})
+}
+
+func TestTakeAnyWithClosedInputChannels(t *testing.T) {
+	t.Parallel()
+	ins := []<-chan int{fromSlice(t, []int{1, 2}), fromSlice(t, []int{3}), fromSlice(t, []int(nil))}
+	values := ToSlice(context.TODO(), TakeAny(context.TODO(), ins, 10))
+	sort.Ints(values)
+	expectedSlice := []int{1, 2, 3}
+	if !reflect.DeepEqual(values, expectedSlice) {
+		t.Errorf("wrong values returned\nwant %#v\ngot  %#v", expectedSlice, values)
+	}
+}
+
+func TestTakeAnyWithContextCancellation(t *testing.T) {
+	t.Parallel()
+	ch := startGenerator(t, "", func(v string) (string, bool) {
+		return v, true
+	}, func() { time.Sleep(time.Second) })
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	values := ToSlice(context.TODO(), TakeAny(ctx, []<-chan string{ch}, 5))
+	expectedSlice := []string(nil)
+	if !reflect.DeepEqual(values, expectedSlice) {
+		t.Errorf("wrong values returned\nwant %#v\ngot  %#v", expectedSlice, values)
+	}
+}
+
+func TestTakeWeighted(t *testing.T) {
+	t.Parallel()
+	in := fromSlice(t, []string{"abc", "de", "fghi", "j", "k"})
+	values := ToSlice(context.TODO(), TakeWeighted(context.TODO(), in, 10, func(v string) int { return len(v) }))
+	expectedSlice := []string{"abc", "de", "fghi", "j"}
+	if !reflect.DeepEqual(values, expectedSlice) {
+		t.Errorf("wrong values returned\nwant %#v\ngot  %#v", expectedSlice, values)
+	}
+
+	in = fromSlice(t, []string{"abc", "defghijk", "l"})
+	values = ToSlice(context.TODO(), TakeWeighted(context.TODO(), in, 10, func(v string) int { return len(v) }))
+	expectedSlice = []string{"abc"}
+	if !reflect.DeepEqual(values, expectedSlice) {
+		t.Errorf("wrong values returned\nwant %#v\ngot  %#v", expectedSlice, values)
+	}
+}
+
+func TestTakeWeightedWithContextCancellation(t *testing.T) {
+	t.Parallel()
+	ch := startGenerator(t, "", func(v string) (string, bool) {
+		return v, true
+	}, func() { time.Sleep(time.Second) })
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	values := ToSlice(context.TODO(), TakeWeighted(ctx, ch, 10, func(string) int { return 1 }))
+	expectedSlice := []string(nil)
+	if !reflect.DeepEqual(values, expectedSlice) {
+		t.Errorf("wrong values returned\nwant %#v\ngot  %#v", expectedSlice, values)
+	}
+}
+
+func TestTakeUntilSignal(t *testing.T) {
+	t.Parallel()
+
+	t.Run("stops when the signal fires", func(t *testing.T) {
+		t.Parallel()
+		in := make(chan int)
+		stop := make(chan struct{})
+		out := TakeUntilSignal(context.TODO(), in, stop)
+
+		for i := 1; i <= 3; i++ {
+			in <- i
+			if got := <-out; got != i {
+				t.Errorf("wrong value returned\nwant %d\ngot  %d", i, got)
+			}
+		}
+		close(stop)
+		if values := ToSlice(context.TODO(), out); values != nil {
+			t.Errorf("unexpected values after the signal: %#v", values)
+		}
+	})
+
+	t.Run("never firing signal", func(t *testing.T) {
+		t.Parallel()
+		values := ToSlice(context.TODO(), TakeUntilSignal(context.TODO(), fromSlice(t, []int{1, 2, 3}), make(chan struct{})))
+		expected := []int{1, 2, 3}
+		if !reflect.DeepEqual(values, expected) {
+			t.Errorf("wrong values returned\nwant %#v\ngot  %#v", expected, values)
+		}
+	})
+}
+
+func TestTakeUntilSignalWithContextCancellation(t *testing.T) {
+	t.Parallel()
+	ch := startGenerator(t, 0, func(p int) (int, bool) {
+		return p + 1, true
+	}, func() { time.Sleep(time.Second) })
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	values := ToSlice(context.TODO(), TakeUntilSignal(ctx, ch, nil))
+	if values != nil {
+		t.Errorf("unexpected non-nil slice: %#v", values)
+	}
+}
@@ -0,0 +1,102 @@
+package channels
+
+import (
+	"context"
+	"time"
+)
+
+// WeightedThrottle takes an input channel and returns an output channel that
+// emits the values from the input channel limited by a token bucket: the
+// bucket holds up to burst tokens and is refilled at ratePerSec tokens per
+// second, and each value takes weight(value) tokens from the bucket, waiting
+// until there are enough tokens available. Values that weigh more than burst
+// take burst tokens, and values with a negative weight take no tokens. The
+// bucket starts full.
+//
+// ratePerSec must be positive and burst must be at least 1: WeightedThrottle
+// panics otherwise.
+//
+// The output channel is unbuffered.
+//
+// See WithClock for changing the clock used to refill the bucket.
+//
+// This is a non-blocking function: it launches a goroutine and returns the
+// channel for consumption. In order to stop the inner goroutine, one can close
+// the input channel or cancel the provided context.
+//
+// The output channel is always closed on cancellation, even if the input
+// channel is never closed.
+func WeightedThrottle[T any](ctx context.Context, in <-chan T, ratePerSec float64, burst int, weight func(T) int, opts ...TimeOption) <-chan T {
+	if !(ratePerSec > 0) {
+		panic("channels: WeightedThrottle ratePerSec must be positive")
+	}
+	if burst < 1 {
+		panic("channels: WeightedThrottle burst must be at least 1")
+	}
+	options := newTimeOptions(opts)
+	out := make(chan T)
+	go func() {
+		defer close(out)
+		bucket := tokenBucket{
+			clock:  options.clock,
+			timer:  lazyTimer{clock: options.clock},
+			rate:   ratePerSec,
+			burst:  float64(burst),
+			tokens: float64(burst),
+			last:   options.clock.Now(),
+		}
+		defer bucket.timer.Stop()
+		receiveLoop(ctx, in, func(v T) bool {
+			return bucket.wait(ctx, weight(v)) && trySend(ctx, out, v)
+		})
+	}()
+	return out
+}
+
+type tokenBucket struct {
+	clock  Clock
+	timer  lazyTimer
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+// wait blocks until there are n tokens available in the bucket and takes
+// them. It returns false if the context is cancelled while waiting.
+func (b *tokenBucket) wait(ctx context.Context, n int) bool {
+	needed := float64(n)
+	if needed > b.burst {
+		needed = b.burst
+	} else if needed < 0 {
+		needed = 0
+	}
+
+	b.refill()
+	if missing := needed - b.tokens; missing > 0 {
+		b.timer.Reset(time.Duration(missing / b.rate * float64(time.Second)))
+		select {
+		case <-b.timer.C():
+		case <-ctx.Done():
+			return false
+		}
+		b.refill()
+		// the timer fired, so the missing tokens are available even if
+		// the clock reports slightly less time elapsed.
+		if b.tokens < needed {
+			b.tokens = needed
+		}
+	}
+	b.tokens -= needed
+	return true
+}
+
+// refill adds the tokens accumulated since the last refill to the bucket.
+func (b *tokenBucket) refill() {
+	now := b.clock.Now()
+	b.tokens += now.Sub(b.last).Seconds() * b.rate
+	if b.tokens > b.burst {
+		b.tokens = b.burst
+	}
+	b.last = now
+}
@@ -0,0 +1,137 @@
+package channels
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestWeightedThrottle(t *testing.T) {
+	t.Parallel()
+	var mu sync.Mutex
+	current := time.Date(2021, time.December, 1, 0, 0, 0, 0, time.UTC)
+	now := func() time.Time {
+		mu.Lock()
+		defer mu.Unlock()
+		return current
+	}
+	advance := func(d time.Duration) {
+		mu.Lock()
+		defer mu.Unlock()
+		current = current.Add(d)
+	}
+	delays := make(chan time.Duration, 10)
+	timers := make(chan chan time.Time, 10)
+	after := func(d time.Duration) <-chan time.Time {
+		timer := make(chan time.Time, 1)
+		delays <- d
+		timers <- timer
+		return timer
+	}
+	in := make(chan int)
+	out := WeightedThrottle(context.TODO(), in, 10, 10, func(v int) int { return v }, WithClock(testClock{now: now, after: after}))
+
+	// the bucket starts full, so 4 and 6 go through without waiting.
+	for _, v := range []int{4, 6} {
+		in <- v
+		if got := <-out; got != v {
+			t.Errorf("wrong value returned\nwant %d\ngot  %d", v, got)
+		}
+	}
+
+	// the bucket is empty: 3 tokens take 300ms to refill, and values
+	// heavier than burst only wait for burst tokens.
+	for _, test := range []struct {
+		value int
+		delay time.Duration
+	}{
+		{3, 300 * time.Millisecond},
+		{20, time.Second},
+	} {
+		in <- test.value
+		if d := <-delays; d != test.delay {
+			t.Errorf("wrong delay for %d\nwant %s\ngot  %s", test.value, test.delay, d)
+		}
+		advance(test.delay)
+		(<-timers) <- now()
+		if got := <-out; got != test.value {
+			t.Errorf("wrong value returned\nwant %d\ngot  %d", test.value, got)
+		}
+	}
+
+	// tokens accumulate while no values are received.
+	advance(500 * time.Millisecond)
+	in <- 5
+	if got := <-out; got != 5 {
+		t.Errorf("wrong value returned\nwant 5\ngot  %d", got)
+	}
+	select {
+	case d := <-delays:
+		t.Errorf("unexpected wait of %s with enough tokens", d)
+	default:
+	}
+
+	close(in)
+	if values := ToSlice(context.TODO(), out); values != nil {
+		t.Errorf("unexpected values after closing input: %#v", values)
+	}
+}
+
+func TestWeightedThrottleInvalidBurst(t *testing.T) {
+	t.Parallel()
+	for _, burst := range []int{0, -1} {
+		burst := burst
+		t.Run(fmt.Sprintf("burst %d", burst), func(t *testing.T) {
+			t.Parallel()
+			defer func() {
+				if recover() == nil {
+					t.Errorf("expected panic for burst %d", burst)
+				}
+			}()
+			WeightedThrottle(context.TODO(), make(chan int), 1, burst, func(int) int { return 1 })
+		})
+	}
+}
+
+func TestWeightedThrottleInvalidRate(t *testing.T) {
+	t.Parallel()
+	var tests = []struct {
+		name string
+		rate float64
+	}{
+		{"zero", 0},
+		{"negative", -1},
+		{"NaN", math.NaN()},
+	}
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+			defer func() {
+				if recover() == nil {
+					t.Errorf("expected panic for rate %v", test.rate)
+				}
+			}()
+			WeightedThrottle(context.TODO(), make(chan int), test.rate, 1, func(int) int { return 1 })
+		})
+	}
+}
+
+func TestWeightedThrottleWithContextCancellation(t *testing.T) {
+	t.Parallel()
+	ch := startGenerator(t, 0, func(p int) (int, bool) {
+		return p + 1, true
+	}, nil)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	values := ToSlice(context.TODO(), WeightedThrottle(ctx, ch, 1, 1, func(int) int { return 1 }))
+	expected := []int{1}
+	if !reflect.DeepEqual(values, expected) {
+		t.Errorf("wrong values returned\nwant %#v\ngot  %#v", expected, values)
+	}
+}
@@ -0,0 +1,216 @@
+package channels
+
+import (
+	"context"
+	"time"
+)
+
+// Clock provides the current time and timers to operators that depend on the
+// passage of time. It can be replaced with WithClock, which is mostly useful
+// in tests.
+type Clock interface {
+	// Now returns the current time.
+	Now() time.Time
+
+	// NewTimer returns a Timer that fires once d elapses, like
+	// time.NewTimer.
+	NewTimer(d time.Duration) Timer
+}
+
+// Timer is a timer created by a Clock. Operators reuse a single Timer,
+// resetting it instead of creating a new one for each wait, and stop it once
+// they're done.
+type Timer interface {
+	// C returns the channel that receives the current time when the timer
+	// fires.
+	C() <-chan time.Time
+
+	// Stop prevents the timer from firing. A value the timer sent before
+	// it was stopped is discarded, so it's never received from C.
+	Stop()
+
+	// Reset stops the timer, like Stop, and makes it fire once d elapses.
+	Reset(d time.Duration)
+}
+
+type realClock struct{}
+
+func (realClock) Now() time.Time {
+	return time.Now()
+}
+
+func (realClock) NewTimer(d time.Duration) Timer {
+	return realTimer{time.NewTimer(d)}
+}
+
+type realTimer struct {
+	timer *time.Timer
+}
+
+func (t realTimer) C() <-chan time.Time {
+	return t.timer.C
+}
+
+func (t realTimer) Stop() {
+	if !t.timer.Stop() {
+		select {
+		case <-t.timer.C:
+		default:
+		}
+	}
+}
+
+func (t realTimer) Reset(d time.Duration) {
+	t.Stop()
+	t.timer.Reset(d)
+}
+
+// lazyTimer is a Timer that's only created by the clock on the first call to
+// Reset, for operators that may never need to wait.
+type lazyTimer struct {
+	clock Clock
+	timer Timer
+}
+
+func (t *lazyTimer) C() <-chan time.Time {
+	if t.timer == nil {
+		return nil
+	}
+	return t.timer.C()
+}
+
+func (t *lazyTimer) Stop() {
+	if t.timer != nil {
+		t.timer.Stop()
+	}
+}
+
+func (t *lazyTimer) Reset(d time.Duration) {
+	if t.timer == nil {
+		t.timer = t.clock.NewTimer(d)
+		return
+	}
+	t.timer.Reset(d)
+}
+
+// TimeOption is an option that can be provided to operators that depend on
+// the passage of time. It's also accepted by operators that take their own
+// option types, such as TakeEvery, Chunk, CountWindow and Retry.
+type TimeOption interface {
+	ThrottleOption
+	ChunkOption
+	WindowOption
+	RetryOption
+	applyTime(*timeOptions)
+}
+
+type timeOptions struct {
+	clock Clock
+}
+
+type timeOption func(*timeOptions)
+
+func (o timeOption) applyTime(opts *timeOptions) {
+	o(opts)
+}
+
+func (o timeOption) applyThrottle(opts *throttleOptions) {
+	o(&opts.timeOptions)
+}
+
+func (o timeOption) applyChunk(opts *chunkOptions) {
+	o(&opts.timeOptions)
+}
+
+func (o timeOption) applyWindow(opts *windowOptions) {
+	o(&opts.timeOptions)
+}
+
+func (o timeOption) applyRetry(opts *retryOptions) {
+	o(&opts.timeOptions)
+}
+
+// WithClock sets the clock used by the operator to read the current time and
+// to wait for durations.
+//
+// The default clock uses time.Now and time.NewTimer.
+func WithClock(clock Clock) TimeOption {
+	return timeOption(func(opts *timeOptions) {
+		opts.clock = clock
+	})
+}
+
+func defaultTimeOptions() timeOptions {
+	return timeOptions{clock: realClock{}}
+}
+
+func newTimeOptions(opts []TimeOption) timeOptions {
+	options := defaultTimeOptions()
+	for _, opt := range opts {
+		opt.applyTime(&options)
+	}
+	return options
+}
+
+// Timestamped is a value tagged with the time it was received.
+type Timestamped[T any] struct {
+	Time  time.Time
+	Value T
+}
+
+// Timestamp takes an input channel and returns an output channel that tags
+// each value from the input channel with the time it was received.
+//
+// The capacity of the output channel will be same as the capacity of the input
+// channel.
+//
+// This is a non-blocking function: it launches a goroutine and returns the
+// channel for consumption. In order to stop the inner goroutine, one can close
+// the input channel or cancel the provided context.
+//
+// The output channel is always closed on cancellation, even if the input
+// channel is never closed.
+func Timestamp[T any](ctx context.Context, in <-chan T, opts ...TimeOption) <-chan Timestamped[T] {
+	options := newTimeOptions(opts)
+	return Map(ctx, in, func(v T) Timestamped[T] {
+		return Timestamped[T]{Time: options.clock.Now(), Value: v}
+	})
+}
+
+// IntervalValue is a value tagged with the time elapsed since the previous
+// value was received.
+type IntervalValue[T any] struct {
+	Interval time.Duration
+	Value    T
+}
+
+// TimeInterval takes an input channel and returns an output channel that tags
+// each value from the input channel with the time elapsed since the previous
+// value was received. The interval of the first value is always zero.
+//
+// The capacity of the output channel will be same as the capacity of the input
+// channel.
+//
+// This is a non-blocking function: it launches a goroutine and returns the
+// channel for consumption. In order to stop the inner goroutine, one can close
+// the input channel or cancel the provided context.
+//
+// The output channel is always closed on cancellation, even if the input
+// channel is never closed.
+func TimeInterval[T any](ctx context.Context, in <-chan T, opts ...TimeOption) <-chan IntervalValue[T] {
+	options := newTimeOptions(opts)
+	var (
+		last    time.Time
+		started bool
+	)
+	return Map(ctx, in, func(v T) IntervalValue[T] {
+		now := options.clock.Now()
+		var interval time.Duration
+		if started {
+			interval = now.Sub(last)
+		}
+		last = now
+		started = true
+		return IntervalValue[T]{Interval: interval, Value: v}
+	})
+}
@@ -0,0 +1,165 @@
+package channels
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestTimestamp(t *testing.T) {
+	t.Parallel()
+	ch := startGenerator(t, 0, func(p int) (int, bool) {
+		if p > 2 {
+			return p, false
+		}
+		return p + 1, true
+	}, nil)
+	now, start := fakeClock(time.Second)
+
+	values := ToSlice(context.TODO(), Timestamp(context.TODO(), ch, WithClock(testClock{now: now})))
+	expected := []Timestamped[int]{
+		{Time: start, Value: 1},
+		{Time: start.Add(time.Second), Value: 2},
+		{Time: start.Add(2 * time.Second), Value: 3},
+	}
+	if !reflect.DeepEqual(values, expected) {
+		t.Errorf("wrong values returned\nwant %#v\ngot  %#v", expected, values)
+	}
+	for i := 1; i < len(values); i++ {
+		if !values[i].Time.After(values[i-1].Time) {
+			t.Errorf("non-monotonic timestamps: %s then %s", values[i-1].Time, values[i].Time)
+		}
+	}
+}
+
+func TestTimestampWithContextCancellation(t *testing.T) {
+	t.Parallel()
+	ch := startGenerator(t, "", func(v string) (string, bool) {
+		return v, true
+	}, func() { time.Sleep(time.Second) })
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	values := ToSlice(context.TODO(), Timestamp(ctx, ch))
+	if values != nil {
+		t.Errorf("unexpected non-nil slice: %#v", values)
+	}
+}
+
+func TestRealClockTimer(t *testing.T) {
+	t.Parallel()
+	timer := realClock{}.NewTimer(time.Millisecond)
+	defer timer.Stop()
+	time.Sleep(10 * time.Millisecond)
+
+	// the timer already fired, but the value is discarded by Reset.
+	timer.Reset(time.Hour)
+	select {
+	case <-timer.C():
+		t.Fatal("unexpected fire after reset")
+	case <-time.After(10 * time.Millisecond):
+	}
+
+	timer.Reset(time.Millisecond)
+	select {
+	case <-timer.C():
+	case <-time.After(time.Second):
+		t.Fatal("timer didn't fire after reset")
+	}
+}
+
+// testClock is a Clock whose functions can be replaced in tests. The real
+// clock is used for the functions that aren't set.
+type testClock struct {
+	now   func() time.Time
+	after func(time.Duration) <-chan time.Time
+}
+
+func (c testClock) Now() time.Time {
+	if c.now == nil {
+		return time.Now()
+	}
+	return c.now()
+}
+
+func (c testClock) NewTimer(d time.Duration) Timer {
+	if c.after == nil {
+		return realClock{}.NewTimer(d)
+	}
+	return &testTimer{after: c.after, c: c.after(d)}
+}
+
+// testTimer is a Timer that gets a new channel from after whenever it's
+// reset, so tests can fire each wait individually.
+type testTimer struct {
+	after func(time.Duration) <-chan time.Time
+	c     <-chan time.Time
+}
+
+func (t *testTimer) C() <-chan time.Time {
+	return t.c
+}
+
+func (t *testTimer) Stop() {
+	t.c = nil
+}
+
+func (t *testTimer) Reset(d time.Duration) {
+	t.c = t.after(d)
+}
+
+// fakeClock returns a function that returns start on the first call and
+// advances by step on each following call, along with start.
+func fakeClock(step time.Duration) (func() time.Time, time.Time) {
+	start := time.Date(2021, time.December, 1, 0, 0, 0, 0, time.UTC)
+	current := start
+	return func() time.Time {
+		t := current
+		current = current.Add(step)
+		return t
+	}, start
+}
+
+func TestTimeInterval(t *testing.T) {
+	t.Parallel()
+	ch := startGenerator(t, 0, func(p int) (int, bool) {
+		if p > 3 {
+			return p, false
+		}
+		return p + 1, true
+	}, nil)
+	start := time.Date(2021, time.December, 1, 0, 0, 0, 0, time.UTC)
+	offsets := []time.Duration{0, time.Second, 3 * time.Second, 3*time.Second + 500*time.Millisecond}
+	calls := 0
+	now := func() time.Time {
+		t := start.Add(offsets[calls])
+		calls++
+		return t
+	}
+
+	values := ToSlice(context.TODO(), TimeInterval(context.TODO(), ch, WithClock(testClock{now: now})))
+	expected := []IntervalValue[int]{
+		{Interval: 0, Value: 1},
+		{Interval: time.Second, Value: 2},
+		{Interval: 2 * time.Second, Value: 3},
+		{Interval: 500 * time.Millisecond, Value: 4},
+	}
+	if !reflect.DeepEqual(values, expected) {
+		t.Errorf("wrong values returned\nwant %#v\ngot  %#v", expected, values)
+	}
+}
+
+func TestTimeIntervalWithContextCancellation(t *testing.T) {
+	t.Parallel()
+	ch := startGenerator(t, "", func(v string) (string, bool) {
+		return v, true
+	}, func() { time.Sleep(time.Second) })
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	values := ToSlice(context.TODO(), TimeInterval(ctx, ch))
+	if values != nil {
+		t.Errorf("unexpected non-nil slice: %#v", values)
+	}
+}
@@ -0,0 +1,54 @@
+package channels
+
+import "context"
+
+// ValidationError is a value that failed validation, along with the error
+// returned by the check function.
+type ValidationError[T any] struct {
+	Value T
+	Err   error
+}
+
+// Error returns the message of the underlying error.
+func (e ValidationError[T]) Error() string {
+	return e.Err.Error()
+}
+
+// Unwrap returns the underlying error.
+func (e ValidationError[T]) Unwrap() error {
+	return e.Err
+}
+
+// Validate takes an input channel and a check function, and returns two
+// channels: valid emits the values for which the check function returns nil,
+// and invalid emits the other values, wrapped with the error returned by the
+// check function. Every value from the input channel ends up in exactly one of
+// the two output channels.
+//
+// Both output channels are fed by a single goroutine, so a consumer that
+// doesn't read one of them eventually blocks the other.
+//
+// The capacity of both output channels will be same as the capacity of the
+// input channel.
+//
+// This is a non-blocking function: it launches a goroutine and returns the
+// channels for consumption. In order to stop the inner goroutine, one can
+// close the input channel or cancel the provided context.
+//
+// The output channels are always closed together on cancellation, even if the
+// input channel is never closed.
+func Validate[T any](ctx context.Context, in <-chan T, check func(T) error) (<-chan T, <-chan ValidationError[T]) {
+	valid := make(chan T, cap(in))
+	invalid := make(chan ValidationError[T], cap(in))
+	go func() {
+		defer close(valid)
+		defer close(invalid)
+		receiveLoop(ctx, in, func(v T) bool {
+			if err := check(v); err != nil {
+				return trySend(ctx, invalid, ValidationError[T]{Value: v, Err: err})
+			}
+			return trySend(ctx, valid, v)
+		})
+	}()
+	return valid, invalid
+}
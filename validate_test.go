@@ -0,0 +1,58 @@
+package channels
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestValidate(t *testing.T) {
+	t.Parallel()
+	errNegative := errors.New("negative value")
+	errOdd := errors.New("odd value")
+	check := func(v int) error {
+		if v < 0 {
+			return errNegative
+		}
+		if v%2 != 0 {
+			return errOdd
+		}
+		return nil
+	}
+
+	valid, invalid := Validate(context.TODO(), fromSlice(t, []int{2, -4, 3, 8, -1, 0}), check)
+	gotValid, gotInvalid := collectBoth(valid, invalid)
+
+	expectedValid := []int{2, 8, 0}
+	if !reflect.DeepEqual(gotValid, expectedValid) {
+		t.Errorf("wrong valid values returned\nwant %#v\ngot  %#v", expectedValid, gotValid)
+	}
+	expectedInvalid := []ValidationError[int]{
+		{Value: -4, Err: errNegative},
+		{Value: 3, Err: errOdd},
+		{Value: -1, Err: errNegative},
+	}
+	if !reflect.DeepEqual(gotInvalid, expectedInvalid) {
+		t.Errorf("wrong invalid values returned\nwant %#v\ngot  %#v", expectedInvalid, gotInvalid)
+	}
+	if err := error(gotInvalid[0]); !errors.Is(err, errNegative) || err.Error() != errNegative.Error() {
+		t.Errorf("validation error doesn't wrap the check error: %v", err)
+	}
+}
+
+func TestValidateWithContextCancellation(t *testing.T) {
+	t.Parallel()
+	ch := startGenerator(t, 0, func(p int) (int, bool) {
+		return p + 1, true
+	}, func() { time.Sleep(time.Second) })
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	valid, invalid := Validate(ctx, ch, func(int) error { return nil })
+	gotValid, gotInvalid := collectBoth(valid, invalid)
+	if gotValid != nil || gotInvalid != nil {
+		t.Errorf("unexpected non-nil slices: %#v, %#v", gotValid, gotInvalid)
+	}
+}
@@ -0,0 +1,166 @@
+package channels
+
+import (
+	"context"
+	"time"
+)
+
+// WindowOption is an option that can be provided to operators that group
+// values in time windows. See also WithClock.
+type WindowOption interface {
+	applyWindow(*windowOptions)
+}
+
+type windowOptions struct {
+	timeOptions
+	skipEmpty bool
+}
+
+type windowOption func(*windowOptions)
+
+func (o windowOption) applyWindow(opts *windowOptions) {
+	o(opts)
+}
+
+// WithSkipEmptyWindows makes the operator skip windows without values,
+// instead of emitting them.
+func WithSkipEmptyWindows() WindowOption {
+	return windowOption(func(opts *windowOptions) {
+		opts.skipEmpty = true
+	})
+}
+
+func newWindowOptions(opts []WindowOption) windowOptions {
+	options := windowOptions{timeOptions: defaultTimeOptions()}
+	for _, opt := range opts {
+		opt.applyWindow(&options)
+	}
+	return options
+}
+
+// SlidingAgg takes an input channel and returns an output channel that emits,
+// for each value received, the aggregate of the last size values received.
+// Instead of recomputing the aggregate over the whole window, it's updated
+// incrementally: add folds the incoming value into the aggregate and remove
+// takes out the value leaving the window, so add and remove must be inverse
+// operations, such as addition and subtraction for a rolling sum. The
+// aggregate starts at zero, and the first size-1 aggregates cover only the
+// values received so far. If size is lower than 1, it's treated as 1.
+//
+// The capacity of the output channel will be same as the capacity of the input
+// channel.
+//
+// This is a non-blocking function: it launches a goroutine and returns the
+// channel for consumption. In order to stop the inner goroutine, one can close
+// the input channel or cancel the provided context.
+//
+// The output channel is always closed on cancellation, even if the input
+// channel is never closed.
+func SlidingAgg[T, A any](ctx context.Context, in <-chan T, size int, add func(A, T) A, remove func(A, T) A, zero A) <-chan A {
+	if size < 1 {
+		size = 1
+	}
+	window := make([]T, 0, size)
+	next := 0
+	acc := zero
+	return Map(ctx, in, func(v T) A {
+		if len(window) < size {
+			window = append(window, v)
+		} else {
+			acc = remove(acc, window[next])
+			window[next] = v
+			next = (next + 1) % size
+		}
+		acc = add(acc, v)
+		return acc
+	})
+}
+
+// CountWindow takes an input channel and returns an output channel that emits
+// the number of values received in each tumbling window of duration d. The
+// first window starts when CountWindow is called, and each window starts when
+// the previous one ends. Windows without values are emitted as 0, unless
+// WithSkipEmptyWindows is provided. When the input channel is closed, the
+// count of the final, partial window is emitted before the output channel is
+// closed.
+//
+// The output channel is unbuffered.
+//
+// See WithClock for changing the clock used to wait for d.
+//
+// This is a non-blocking function: it launches a goroutine and returns the
+// channel for consumption. In order to stop the inner goroutine, one can close
+// the input channel or cancel the provided context.
+//
+// The output channel is always closed on cancellation, even if the input
+// channel is never closed.
+func CountWindow[T any](ctx context.Context, in <-chan T, d time.Duration, opts ...WindowOption) <-chan int {
+	options := newWindowOptions(opts)
+	out := make(chan int)
+	go func() {
+		defer close(out)
+		emit := func(count int) bool {
+			if count == 0 && options.skipEmpty {
+				return true
+			}
+			return trySend(ctx, out, count)
+		}
+		count := 0
+		timer := options.clock.NewTimer(d)
+		defer timer.Stop()
+		for {
+			select {
+			case _, ok := <-in:
+				if !ok {
+					emit(count)
+					return
+				}
+				count++
+			case <-timer.C():
+				if !emit(count) {
+					return
+				}
+				count = 0
+				timer.Reset(d)
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return out
+}
+
+// SlidingTimeWindow takes an input channel and returns an output channel that
+// emits, for each value received, the values received in the last d, in the
+// order they were received, including the value just received. Each value is
+// timestamped by the ts function, and values whose timestamp is more than d
+// before the timestamp of the value just received are evicted from the
+// window, so the window follows event time rather than the time values
+// arrive. Eviction only happens when a value is received, and values are
+// evicted from the oldest, so values should be received in timestamp order.
+//
+// Each emitted slice is a fresh copy of the window, so consumers can keep or
+// modify it.
+//
+// The capacity of the output channel will be same as the capacity of the input
+// channel.
+//
+// This is a non-blocking function: it launches a goroutine and returns the
+// channel for consumption. In order to stop the inner goroutine, one can close
+// the input channel or cancel the provided context.
+//
+// The output channel is always closed on cancellation, even if the input
+// channel is never closed.
+func SlidingTimeWindow[T any](ctx context.Context, in <-chan T, d time.Duration, ts func(T) time.Time) <-chan []T {
+	var window []T
+	return Map(ctx, in, func(v T) []T {
+		window = append(window, v)
+		cutoff := ts(v).Add(-d)
+		evicted := 0
+		for evicted < len(window) && ts(window[evicted]).Before(cutoff) {
+			evicted++
+		}
+		window = append(window[:0], window[evicted:]...)
+		return append([]T(nil), window...)
+	})
+}
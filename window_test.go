@@ -0,0 +1,149 @@
+package channels
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestSlidingAgg(t *testing.T) {
+	t.Parallel()
+	input := []int{3, 1, 4, 1, 5, 9, 2, 6, 5, 3}
+	const size = 3
+
+	add := func(acc, v int) int { return acc + v }
+	sub := func(acc, v int) int { return acc - v }
+	values := ToSlice(context.TODO(), SlidingAgg(context.TODO(), fromSlice(t, input), size, add, sub, 0))
+
+	expected := make([]int, len(input))
+	for i := range input {
+		for j := i; j >= 0 && j > i-size; j-- {
+			expected[i] += input[j]
+		}
+	}
+	if !reflect.DeepEqual(values, expected) {
+		t.Errorf("wrong values returned\nwant %#v\ngot  %#v", expected, values)
+	}
+}
+
+func TestSlidingAggWithContextCancellation(t *testing.T) {
+	t.Parallel()
+	ch := startGenerator(t, 0, func(p int) (int, bool) {
+		return p + 1, true
+	}, func() { time.Sleep(time.Second) })
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	add := func(acc, v int) int { return acc + v }
+	sub := func(acc, v int) int { return acc - v }
+	values := ToSlice(context.TODO(), SlidingAgg(ctx, ch, 2, add, sub, 0))
+	if values != nil {
+		t.Errorf("unexpected non-nil slice: %#v", values)
+	}
+}
+
+func TestCountWindow(t *testing.T) {
+	t.Parallel()
+	var tests = []struct {
+		name     string
+		opts     []WindowOption
+		expected []int
+	}{
+		{"with empty windows", nil, []int{3, 0, 1, 2}},
+		{"skip empty windows", []WindowOption{WithSkipEmptyWindows()}, []int{3, 1, 2}},
+	}
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+			in := make(chan string)
+			timers := make(chan chan time.Time, 10)
+			after := func(time.Duration) <-chan time.Time {
+				timer := make(chan time.Time, 1)
+				timers <- timer
+				return timer
+			}
+			opts := append([]WindowOption{WithClock(testClock{after: after})}, test.opts...)
+			out := CountWindow(context.TODO(), in, time.Second, opts...)
+
+			var values []int
+			done := make(chan struct{})
+			go func() {
+				defer close(done)
+				values = ToSlice(context.TODO(), out)
+			}()
+
+			// windows: 3 values, no values, 1 value and a partial window
+			// with 2 values.
+			for _, window := range []int{3, 0, 1} {
+				timer := <-timers
+				for i := 0; i < window; i++ {
+					in <- "x"
+				}
+				timer <- time.Now()
+			}
+			<-timers
+			in <- "x"
+			in <- "x"
+			close(in)
+			<-done
+
+			if !reflect.DeepEqual(values, test.expected) {
+				t.Errorf("wrong values returned\nwant %#v\ngot  %#v", test.expected, values)
+			}
+		})
+	}
+}
+
+func TestCountWindowWithContextCancellation(t *testing.T) {
+	t.Parallel()
+	ch := startGenerator(t, 0, func(p int) (int, bool) {
+		return p + 1, true
+	}, func() { time.Sleep(time.Second) })
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	values := ToSlice(context.TODO(), CountWindow(ctx, ch, time.Second))
+	if values != nil {
+		t.Errorf("unexpected non-nil slice: %#v", values)
+	}
+}
+
+func TestSlidingTimeWindow(t *testing.T) {
+	t.Parallel()
+	// each value is the number of seconds since start, which is far in the
+	// past, so the window must follow the timestamps of the values rather
+	// than the current time.
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	offsets := []int{0, 1, 2, 5, 6, 20}
+	ts := func(v int) time.Time { return start.Add(time.Duration(v) * time.Second) }
+
+	out := SlidingTimeWindow(context.TODO(), fromSlice(t, offsets), 4*time.Second, ts)
+	values := ToSlice(context.TODO(), out)
+	expected := [][]int{
+		{0},
+		{0, 1},
+		{0, 1, 2},
+		{1, 2, 5},
+		{2, 5, 6},
+		{20},
+	}
+	if !reflect.DeepEqual(values, expected) {
+		t.Errorf("wrong values returned\nwant %#v\ngot  %#v", expected, values)
+	}
+}
+
+func TestSlidingTimeWindowWithContextCancellation(t *testing.T) {
+	t.Parallel()
+	ch := startGenerator(t, 0, func(p int) (int, bool) {
+		return p + 1, true
+	}, func() { time.Sleep(time.Second) })
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	values := ToSlice(context.TODO(), SlidingTimeWindow(ctx, ch, time.Second, func(int) time.Time { return time.Now() }))
+	if values != nil {
+		t.Errorf("unexpected non-nil slice: %#v", values)
+	}
+}
@@ -0,0 +1,137 @@
+package channels
+
+import "context"
+
+// Tuple3 groups three values of possibly different types.
+type Tuple3[A, B, C any] struct {
+	First  A
+	Second B
+	Third  C
+}
+
+// Zip3 takes three input channels and returns an output channel that pairs
+// values from the three input channels in the order they're received: the
+// first value from each input channel forms the first tuple, the second value
+// from each input channel forms the second tuple and so on.
+//
+// The output channel is unbuffered.
+//
+// This is a non-blocking function: it launches a goroutine and returns the
+// channel for consumption. In order to stop the inner goroutine, one can close
+// any of the input channels or cancel the provided context. Values received
+// from the other input channels for an incomplete tuple are discarded.
+//
+// The output channel is always closed on cancellation or when any of the
+// input channels is closed.
+func Zip3[A, B, C any](ctx context.Context, a <-chan A, b <-chan B, c <-chan C) <-chan Tuple3[A, B, C] {
+	out := make(chan Tuple3[A, B, C])
+	go func() {
+		defer close(out)
+		for {
+			var (
+				t  Tuple3[A, B, C]
+				ok bool
+			)
+			if t.First, ok = tryReceive(ctx, a); !ok {
+				return
+			}
+			if t.Second, ok = tryReceive(ctx, b); !ok {
+				return
+			}
+			if t.Third, ok = tryReceive(ctx, c); !ok {
+				return
+			}
+			if !trySend(ctx, out, t) {
+				return
+			}
+		}
+	}()
+	return out
+}
+
+// ZipWith takes two input channels and returns an output channel that
+// combines values from the two input channels in the order they're received,
+// using the provided function: the first value from each input channel is
+// combined into the first output value, the second value from each input
+// channel into the second output value and so on.
+//
+// The output channel is unbuffered.
+//
+// This is a non-blocking function: it launches a goroutine and returns the
+// channel for consumption. In order to stop the inner goroutine, one can close
+// any of the input channels or cancel the provided context. A value received
+// from the other input channel for an incomplete pair is discarded.
+//
+// The output channel is always closed on cancellation or when any of the
+// input channels is closed.
+func ZipWith[A, B, C any](ctx context.Context, a <-chan A, b <-chan B, combine func(A, B) C) <-chan C {
+	out := make(chan C)
+	go func() {
+		defer close(out)
+		for {
+			va, ok := tryReceive(ctx, a)
+			if !ok {
+				return
+			}
+			vb, ok := tryReceive(ctx, b)
+			if !ok {
+				return
+			}
+			if !trySend(ctx, out, combine(va, vb)) {
+				return
+			}
+		}
+	}()
+	return out
+}
+
+// Transpose takes an input channel of rows and returns width output channels,
+// one per column: the i-th output channel receives the i-th element of each
+// row, in the order the rows are received. Rows shorter than width are padded
+// with zero values and elements beyond width are discarded, so the output
+// channels always stay aligned. If width is lower than 1, a single output
+// channel is returned.
+//
+// All output channels are fed by a single goroutine, one row at a time, so a
+// consumer that doesn't read one of them eventually blocks the others.
+//
+// The capacity of each output channel will be same as the capacity of the
+// input channel.
+//
+// This is a non-blocking function: it launches a goroutine and returns the
+// channels for consumption. In order to stop the inner goroutine, one can
+// close the input channel or cancel the provided context.
+//
+// The output channels are always closed on cancellation, even if the input
+// channel is never closed.
+func Transpose[T any](ctx context.Context, in <-chan []T, width int) []<-chan T {
+	if width < 1 {
+		width = 1
+	}
+	chs := make([]chan T, width)
+	outs := make([]<-chan T, width)
+	for i := range chs {
+		chs[i] = make(chan T, cap(in))
+		outs[i] = chs[i]
+	}
+	go func() {
+		defer func() {
+			for _, ch := range chs {
+				close(ch)
+			}
+		}()
+		receiveLoop(ctx, in, func(row []T) bool {
+			for i, ch := range chs {
+				var v T
+				if i < len(row) {
+					v = row[i]
+				}
+				if !trySend(ctx, ch, v) {
+					return false
+				}
+			}
+			return true
+		})
+	}()
+	return outs
+}
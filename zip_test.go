@@ -0,0 +1,123 @@
+package channels
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestZip3(t *testing.T) {
+	t.Parallel()
+	ids := fromSlice(t, []int{1, 2, 3, 4})
+	names := fromSlice(t, []string{"a", "b", "c"})
+	scores := fromSlice(t, []float64{1.5, 2.5, 3.5, 4.5, 5.5})
+
+	values := ToSlice(context.TODO(), Zip3(context.TODO(), ids, names, scores))
+	expectedSlice := []Tuple3[int, string, float64]{
+		{First: 1, Second: "a", Third: 1.5},
+		{First: 2, Second: "b", Third: 2.5},
+		{First: 3, Second: "c", Third: 3.5},
+	}
+	if !reflect.DeepEqual(values, expectedSlice) {
+		t.Errorf("wrong values returned\nwant %#v\ngot  %#v", expectedSlice, values)
+	}
+}
+
+func TestZip3EmptyInput(t *testing.T) {
+	t.Parallel()
+	ids := fromSlice(t, []int{1, 2})
+	names := fromSlice(t, []string{"a", "b"})
+	scores := fromSlice(t, []float64(nil))
+
+	values := ToSlice(context.TODO(), Zip3(context.TODO(), ids, names, scores))
+	if values != nil {
+		t.Errorf("unexpected non-nil slice: %#v", values)
+	}
+}
+
+func TestZip3WithContextCancellation(t *testing.T) {
+	t.Parallel()
+	ids := fromSlice(t, []int{1, 2, 3})
+	names := fromSlice(t, []string{"a", "b", "c"})
+	scores := startGenerator(t, 0.0, func(p float64) (float64, bool) {
+		return p + 1, true
+	}, func() { time.Sleep(time.Second) })
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	values := ToSlice(context.TODO(), Zip3(ctx, ids, names, scores))
+	if values != nil {
+		t.Errorf("unexpected non-nil slice: %#v", values)
+	}
+}
+
+func TestZipWith(t *testing.T) {
+	t.Parallel()
+	a := fromSlice(t, []int{1, 2, 3, 4, 5})
+	b := fromSlice(t, []int{10, 20, 30})
+
+	values := ToSlice(context.TODO(), ZipWith(context.TODO(), a, b, func(x, y int) int {
+		return x + y
+	}))
+	expectedSlice := []int{11, 22, 33}
+	if !reflect.DeepEqual(values, expectedSlice) {
+		t.Errorf("wrong values returned\nwant %#v\ngot  %#v", expectedSlice, values)
+	}
+}
+
+func TestZipWithWithContextCancellation(t *testing.T) {
+	t.Parallel()
+	a := fromSlice(t, []int{1, 2, 3})
+	b := startGenerator(t, 0, func(p int) (int, bool) {
+		return p + 1, true
+	}, func() { time.Sleep(time.Second) })
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	values := ToSlice(context.TODO(), ZipWith(ctx, a, b, func(x, y int) int {
+		return x + y
+	}))
+	if values != nil {
+		t.Errorf("unexpected non-nil slice: %#v", values)
+	}
+}
+
+func TestTranspose(t *testing.T) {
+	t.Parallel()
+	ch := fromSlice(t, [][]int{{1, 2, 3}, {4, 5, 6}, {7}, {8, 9, 10, 11}})
+
+	outs := Transpose(context.TODO(), ch, 3)
+	if len(outs) != 3 {
+		t.Fatalf("wrong number of channels returned\nwant 3\ngot  %d", len(outs))
+	}
+	results := make([]chan []int, len(outs))
+	for i, out := range outs {
+		results[i] = make(chan []int, 1)
+		go func(i int, out <-chan int) {
+			results[i] <- ToSlice(context.TODO(), out)
+		}(i, out)
+	}
+
+	expected := [][]int{{1, 4, 7, 8}, {2, 5, 0, 9}, {3, 6, 0, 10}}
+	for i, result := range results {
+		if got := <-result; !reflect.DeepEqual(got, expected[i]) {
+			t.Errorf("wrong values returned for column %d\nwant %#v\ngot  %#v", i, expected[i], got)
+		}
+	}
+}
+
+func TestTransposeWithContextCancellation(t *testing.T) {
+	t.Parallel()
+	ch := startGenerator(t, nil, func(row []int) ([]int, bool) {
+		return []int{1, 2}, true
+	}, func() { time.Sleep(time.Second) })
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	for _, out := range Transpose(ctx, ch, 2) {
+		if values := ToSlice(context.TODO(), out); values != nil {
+			t.Errorf("unexpected non-nil slice: %#v", values)
+		}
+	}
+}